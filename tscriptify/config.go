@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration file (YAML or JSON) given with -config.
+type Config struct {
+	Static   bool     `yaml:"static"`   // Analyze the packages from source, see -static
+	InModule bool     `yaml:"inModule"` // See -in-module
+	Targets  []Target `yaml:"targets"`
+
+	dir string // Directory of the file, relative paths are resolved from it
+}
+
+// Target is a generated file (or directory) with its models and converter options.
+type Target struct {
+	Package      string        `yaml:"package"`
+	Packages     []string      `yaml:"packages"` // More packages, as `path` or `alias=path`
+	Target       string        `yaml:"target"`
+	TargetDir    string        `yaml:"targetDir"`
+	Structs      []string      `yaml:"structs"` // Struct names (`alias.Name` in other packages), Go files or package directories
+	Include      string        `yaml:"include"` // Regular expressions selecting the structs found in files and directories
+	Exclude      string        `yaml:"exclude"`
+	Annotated    bool          `yaml:"annotated"` // Only structs with a //tscriptify:export comment
+	Enums        []string      `yaml:"enums"`     // Variables with enum values (see typescriptify.AddEnum), as structs
+	Imports      []string      `yaml:"imports"`
+	ManagedTypes []ManagedType `yaml:"managedTypes"`
+	Snapshot     string        `yaml:"snapshot"` // Snapshot file for `tscriptify diff`
+	Report       string        `yaml:"report"`   // Report file for `tscriptify diff`
+	// PackageFiles are the file names (without extension) of the packages with targetDir, by package path,
+	// see typescriptify.WithPackageFile.
+	PackageFiles map[string]string `yaml:"packageFiles"`
+
+	Prefix            string           `yaml:"prefix"`
+	Suffix            string           `yaml:"suffix"`
+	Indent            string           `yaml:"indent"`
+	Interface         bool             `yaml:"interface"`
+	InterfaceAndClass bool             `yaml:"interfaceAndClass"`
+	InterfacePrefix   string           `yaml:"interfacePrefix"`
+	Declaration       bool             `yaml:"declaration"`
+	JavaScript        bool             `yaml:"javascript"`
+	Zod               bool             `yaml:"zod"`
+	Readonly          bool             `yaml:"readonly"`
+	AllOptional       bool             `yaml:"allOptional"`
+	CamelCase         bool             `yaml:"camelCase"`
+	CamelCaseOptions  CamelCaseOptions `yaml:"camelCaseOptions"`
+	CreateFromMethod  bool             `yaml:"createFromMethod"`
+	ValidateTags      bool             `yaml:"validateTags"`
+	DontExport        bool             `yaml:"dontExport"`
+	NoConstructor     bool             `yaml:"noConstructor"`
+	Strict            bool             `yaml:"strict"`
+	Order             string           `yaml:"order"`
+	Backup            string           `yaml:"backup"`
+	BackupKeep        int              `yaml:"backupKeep"`
+}
+
+// ManagedType sets the TypeScript type of all fields of a Go type, see typescriptify.ManageTypeName.
+type ManagedType struct {
+	Type        string `yaml:"type"` // Package path and name, i.e. `time.Time`
+	TSType      string `yaml:"tsType"`
+	TSTransform string `yaml:"tsTransform"`
+	TSDoc       string `yaml:"tsDoc"`
+	ImportFrom  string `yaml:"importFrom"`
+}
+
+func (m ManagedType) TypeOptions() typescriptify.TypeOptions {
+	return typescriptify.TypeOptions{TSType: m.TSType, TSTransform: m.TSTransform, TSDoc: m.TSDoc, ImportFrom: m.ImportFrom}
+}
+
+// CamelCaseOptions are the options of camelCase field names, see typescriptify.CamelCaseOptions.
+type CamelCaseOptions struct {
+	PreserveConsecutiveUppercase bool `yaml:"preserveConsecutiveUppercase"`
+}
+
+// LoadConfig reads a configuration file, JSON is read as YAML.
+func LoadConfig(fileName string) (*Config, error) {
+	byts, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	config := new(Config)
+	decoder := yaml.NewDecoder(bytes.NewReader(byts))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", fileName, err)
+	}
+	config.dir = filepath.Dir(fileName)
+	return config, nil
+}
+
+// Params returns the parameters to convert a target. Options set on the command line (i.e. -check or
+// -verbose) are taken from flags.
+func (c *Config) Params(target Target, flags Params) (Params, error) {
+	order := target.Order
+	if len(order) == 0 {
+		order = string(typescriptify.OrderInsertion)
+	}
+	paths := target.Packages
+	if len(target.Package) > 0 {
+		paths = append([]string{target.Package}, paths...)
+	}
+	filter, err := newStructFilter(target.Include, target.Exclude, target.Annotated)
+	if err != nil {
+		return Params{}, fmt.Errorf("invalid configuration %s: %w", c.path(target.Target+target.TargetDir), err)
+	}
+	packages, err := targetPackages(paths, target.Structs, target.Enums, c.dir, filter)
+	if err != nil {
+		return Params{}, fmt.Errorf("invalid configuration %s: %w", c.path(target.Target+target.TargetDir), err)
+	}
+	return Params{
+		Packages:          packages,
+		TargetFile:        c.path(target.Target),
+		TargetDir:         c.path(target.TargetDir),
+		Order:             order,
+		CustomImports:     target.Imports,
+		ManagedTypes:      target.ManagedTypes,
+		Interface:         target.Interface,
+		InterfaceAndClass: target.InterfaceAndClass,
+		InterfacePrefix:   target.InterfacePrefix,
+		Declaration:       target.Declaration,
+		JavaScript:        target.JavaScript,
+		Zod:               target.Zod,
+		Readonly:          target.Readonly,
+		AllOptional:       target.AllOptional,
+		CamelCase:         target.CamelCase,
+		CamelCaseOptions:  target.CamelCaseOptions,
+		CreateFromMethod:  target.CreateFromMethod,
+		PackageFiles:      target.PackageFiles,
+		ValidateTags:      target.ValidateTags,
+		DontExport:        target.DontExport,
+		NoConstructor:     target.NoConstructor,
+		Strict:            target.Strict || flags.Strict,
+		Prefix:            target.Prefix,
+		Suffix:            target.Suffix,
+		Indent:            target.Indent,
+		BackupDir:         c.path(target.Backup),
+		BackupKeep:        target.BackupKeep,
+		Static:            c.Static || flags.Static,
+		InModule:          c.InModule || flags.InModule,
+		LocalPkg:          flags.LocalPkg,
+		Check:             flags.Check,
+		Diff:              flags.Diff,
+		Snapshot:          c.path(target.Snapshot),
+		Report:            c.path(target.Report),
+		UpdateSnapshot:    flags.UpdateSnapshot,
+		Verbose:           flags.Verbose,
+		Quiet:             flags.Quiet,
+	}, nil
+}
+
+// path returns a path relative to the configuration file.
+func (c *Config) path(fileName string) string {
+	if len(fileName) == 0 || filepath.IsAbs(fileName) {
+		return fileName
+	}
+	return filepath.Join(c.dir, fileName)
+}
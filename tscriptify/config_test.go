@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	fileName := filepath.Join(t.TempDir(), "tscriptify.yaml")
+	assert.Nil(t, os.WriteFile(fileName, []byte(content), 0644))
+	return fileName
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	fileName := writeConfig(t, `
+static: true
+targets:
+  - package: example.com/models
+    target: models.ts
+    structs: [Person, Address]
+    interface: true
+`)
+	config, err := LoadConfig(fileName)
+	assert.Nil(t, err)
+	assert.True(t, config.Static)
+	assert.Equal(t, []Target{{Package: "example.com/models", Target: "models.ts", Structs: []string{"Person", "Address"}, Interface: true}}, config.Targets)
+	assert.Equal(t, filepath.Dir(fileName), config.dir)
+}
+
+func TestLoadConfigConverterOptions(t *testing.T) {
+	t.Parallel()
+
+	fileName := writeConfig(t, `
+targets:
+  - package: example.com/models
+    targetDir: ts
+    packageFiles:
+      example.com/models: people
+    camelCase: true
+    camelCaseOptions:
+      preserveConsecutiveUppercase: true
+    createFromMethod: true
+`)
+	config, err := LoadConfig(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, []Target{{
+		Package:          "example.com/models",
+		TargetDir:        "ts",
+		PackageFiles:     map[string]string{"example.com/models": "people"},
+		CamelCase:        true,
+		CamelCaseOptions: CamelCaseOptions{PreserveConsecutiveUppercase: true},
+		CreateFromMethod: true,
+	}}, config.Targets)
+
+	p, err := config.Params(config.Targets[0], Params{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"example.com/models": "people"}, p.PackageFiles)
+	assert.True(t, p.CamelCaseOptions.PreserveConsecutiveUppercase)
+	assert.True(t, p.CreateFromMethod)
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadConfig(writeConfig(t, `{"targets": [{"package": "example.com/models", "targetDir": "ts"}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []Target{{Package: "example.com/models", TargetDir: "ts"}}, config.Targets)
+}
+
+func TestLoadConfigUnknownField(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{
+		"statik: true\n",
+		"targets:\n  - package: example.com/models\n    interfaces: true\n",
+		"targets:\n  - managedTypes:\n      - type: time.Time\n        ts_type: Date\n",
+	} {
+		_, err := LoadConfig(writeConfig(t, content))
+		assert.NotNil(t, err, content)
+		assert.Contains(t, err.Error(), "invalid configuration", content)
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestConfigParams(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com\n\ngo 1.21\n"), 0644))
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "models"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "models", "models.go"), []byte("package models\n\ntype Person struct{}\n"), 0644))
+
+	config := &Config{dir: dir}
+	target := Target{
+		Package:  "example.com/models",
+		Packages: []string{"other=example.com/other"},
+		Target:   "ts/models.ts",
+		Structs:  []string{"models/models.go", "other.Item"},
+		Enums:    []string{"AllColors"},
+		Backup:   "backups",
+		Snapshot: filepath.Join(dir, "snapshot.ts"),
+		Report:   "report.html",
+		Strict:   true,
+	}
+	p, err := config.Params(target, Params{Check: true, Verbose: true})
+	assert.Nil(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "ts", "models.ts"), p.TargetFile)
+	assert.Equal(t, "", p.TargetDir)
+	assert.Equal(t, filepath.Join(dir, "backups"), p.BackupDir)
+	assert.Equal(t, filepath.Join(dir, "snapshot.ts"), p.Snapshot)
+	assert.Equal(t, filepath.Join(dir, "report.html"), p.Report)
+	assert.Equal(t, []ModelsPackage{
+		{Path: "example.com/models", Alias: "models", Structs: []string{"Person"}, Enums: []string{"AllColors"}},
+		{Path: "example.com/other", Alias: "other", Structs: []string{"Item"}},
+	}, p.Packages)
+	assert.Equal(t, "insertion", p.Order)
+	assert.True(t, p.Strict)
+	assert.True(t, p.Check)
+	assert.True(t, p.Verbose)
+	assert.False(t, p.Static)
+}
+
+func TestConfigParamsInvalidFilter(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{dir: t.TempDir()}
+	_, err := config.Params(Target{Package: "example.com/models", Target: "models.ts", Include: "("}, Params{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// logger logs the progress of tscriptify (and the conversion with -static), set with -quiet or -verbose.
+var logger = newLogger(false, false)
+
+// newLogger returns a text logger (to stderr, without times) at info level, debug level if verbose (with the
+// conversion traces) or error level if quiet. The generator program creates the same logger (see TEMPLATE).
+func newLogger(verbose, quiet bool) *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel(verbose, quiet), ReplaceAttr: withoutTime}))
+}
+
+func logLevel(verbose, quiet bool) slog.Level {
+	switch {
+	case verbose:
+		return slog.LevelDebug
+	case quiet:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+func withoutTime(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && attr.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return attr
+}
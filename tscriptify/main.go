@@ -1,18 +1,37 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/token"
+	"go/format"
+	"log/slog"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"syscall"
 	"text/template"
+	"time"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify"
 )
 
+type arrayPackages []string
+
+func (p *arrayPackages) String() string {
+	return strings.Join(*p, ", ")
+}
+
+func (p *arrayPackages) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
 type arrayImports []string
 
 func (i *arrayImports) String() string {
@@ -24,150 +43,542 @@ func (i *arrayImports) Set(value string) error {
 	return nil
 }
 
-const TEMPLATE = `package main
+const TEMPLATE = `{{ if .EmitProgram }}//go:generate go run .
+
+// Generator of the TypeScript models, created with tscriptify -emit-program. Run it with go generate (or go run .).
+{{ end }}package main
 
 import (
+{{- if .Report }}
+	"encoding/json"
+{{- end }}
+{{- if or .UpdateSnapshot (and .Check (not .Diff)) }}
+	"errors"
+{{- end }}
 	"fmt"
+	"log/slog"
+	"os"
 {{- if .AllOptional }}
 	"reflect"
 {{- end }}
 
-	m "{{ .ModelsPackage }}"
-	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify"
+{{ range .Packages }}	{{ .Alias }} "{{ .Path }}"
+{{ end }}	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify"
 )
 
 func main() {
-{{ if .AllOptional }}
-{{ range .Structs }}	{{ . }}Optional := typescriptify.TagAll(reflect.TypeOf(m.{{ . }}{}), []string{"omitempty"})
-{{ end }}
+{{- if .AllOptional }}
+{{ range $pkg := .Packages }}{{ range .Structs }}	{{ $pkg.Alias }}{{ . }}Optional := optional(reflect.TypeOf({{ $pkg.Alias }}.{{ . }}{}))
+{{ end }}{{ end }}
 {{ end }}
 	t := typescriptify.New()
+	t.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.Level{{ if .Verbose }}Debug{{ else if .Quiet }}Error{{ else }}Info{{ end }}, ReplaceAttr: withoutTime}))
 	t.CreateInterface = {{ .Interface }}
+	t.Declaration = {{ .Declaration }}
+	t.JavaScript = {{ .JavaScript }}
+	t.Zod = {{ .Zod }}
+	t.InterfaceAndClass = {{ .InterfaceAndClass }}
 	t.ReadOnlyFields = {{ .Readonly }}
 	t.CamelCaseFields = {{ .CamelCase }}
+{{- if .CamelCaseOptions.PreserveConsecutiveUppercase }}
+	t.CamelCaseOptions = &typescriptify.CamelCaseOptions{PreserveConsecutiveUppercase: true}
+{{- end }}
+	t.CreateFromMethod = {{ .CreateFromMethod }}
+	t.Order = typescriptify.DeclarationOrder({{ printf "%q" .Order }})
+	t.Prefix = {{ printf "%q" .Prefix }}
+	t.Suffix = {{ printf "%q" .Suffix }}
+{{- if .Indent }}
+	t.Indent = {{ printf "%q" .Indent }}
+{{- end }}
+{{- if .InterfacePrefix }}
+	t.InterfacePrefix = {{ printf "%q" .InterfacePrefix }}
+{{- end }}
+	t.ValidateTags = {{ .ValidateTags }}
+	t.DontExport = {{ .DontExport }}
+	t.CreateConstructor = {{ not .NoConstructor }}
+	t.Strict = {{ .Strict }}
 {{ range $key, $value := .InitParams }}	t.{{ $key }}={{ $value }}
 {{ end }}
-{{ if .AllOptional }}
-{{ range .Structs }}	t.AddTypeWithName({{ . }}Optional, "{{ . }}")
+{{ range $pkgPath, $fileName := .PackageFiles }}	t.WithPackageFile({{ printf "%q" $pkgPath }}, {{ printf "%q" $fileName }})
 {{ end }}
-{{ else }}
-{{ range .Structs }}	t.Add(m.{{ . }}{})
+{{ range .ManagedTypes }}	t.ManageTypeName({{ printf "%q" .Type }}, typescriptify.TypeOptions{TSType: {{ printf "%q" .TSType }}, TSTransform: {{ printf "%q" .TSTransform }}, TSDoc: {{ printf "%q" .TSDoc }}, ImportFrom: {{ printf "%q" .ImportFrom }}})
 {{ end }}
+{{ range $pkg := .Packages }}{{ range .Enums }}	t.AddEnum({{ $pkg.Alias }}.{{ . }})
+{{ end }}{{ end }}
+{{ if .AllOptional }}
+{{ range $pkg := .Packages }}{{ range .Structs }}	t.AddTypeWithName({{ $pkg.Alias }}{{ . }}Optional, "{{ . }}")
+{{ end }}{{ end }}
+{{ else }}
+{{ range $pkg := .Packages }}{{ range .Structs }}	t.Add({{ $pkg.Alias }}.{{ . }}{})
+{{ end }}{{ end }}
 {{ end }}
 {{ range .CustomImports }}	t.AddImport("{{ . }}")
 {{ end }}
-	err := t.ConvertToFile("{{ .TargetFile }}")
+{{ if .Diff }}
+	report, err := t.Diff({{ printf "%q" .Snapshot }})
+{{- if .UpdateSnapshot }}
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+{{- end }}
+	if err != nil {
+		fail(err)
+	}
+	for _, change := range report.Changes {
+		fmt.Println(change)
+	}
+{{- if .Report }}
+	byts, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile({{ printf "%q" .Report }}, byts, 0644); err != nil {
+		fail(err)
+	}
+{{- end }}
+{{- if .UpdateSnapshot }}
+	if err := t.SaveSnapshot({{ printf "%q" .Snapshot }}); err != nil {
+		fail(err)
+	}
+{{- end }}
+	if report.Breaking {
+		fmt.Println("Breaking changes found")
+		os.Exit(1)
+	}
+{{ else if .Check }}
+{{ if .TargetDir }}
+	err := t.CheckDir({{ printf "%q" .TargetDir }})
+{{- else }}
+	err := t.Check({{ printf "%q" .TargetFile }})
+{{- end }}
+	var drift *typescriptify.DriftError
+	if errors.As(err, &drift) {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
 	if err != nil {
-		panic(err.Error())
+		fail(err)
 	}
-	fmt.Println("OK")
+{{ else }}
+{{ if .TargetDir }}
+	err := t.ConvertToDir({{ printf "%q" .TargetDir }})
+{{- else }}
+	err := t.ConvertToFile({{ printf "%q" .TargetFile }})
+{{- end }}
+	if err != nil {
+		fail(err)
+	}
+{{ end }}
+{{- if .Diff }}
+	t.Logger.Info("No breaking changes", slog.String("snapshot", {{ printf "%q" .Snapshot }}))
+{{- else if .Check }}
+	t.Logger.Info("Up to date", slog.String("target", {{ printf "%q" (or .TargetDir .TargetFile) }}))
+{{- else }}
+	t.Logger.Info("Converted", slog.String("target", {{ printf "%q" (or .TargetDir .TargetFile) }}))
+{{- end }}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err.Error())
+	os.Exit(3)
+}
+{{ if .AllOptional }}
+// optional returns the struct with all fields optional.
+func optional(typ reflect.Type) reflect.Type {
+	result, err := typescriptify.TryTagAll(typ, []string{"omitempty"})
+	if err != nil {
+		fail(err)
+	}
+	return result
+}
+{{ end }}
+func withoutTime(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && attr.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return attr
 }`
 
 type Params struct {
-	ModelsPackage string
-	TargetFile    string
-	Structs       []string
-	InitParams    map[string]interface{}
-	CustomImports arrayImports
-	Interface     bool
-	Readonly      bool
-	AllOptional   bool
-	CamelCase     bool
-	LocalPkg      bool
-	Verbose       bool
+	Packages          []ModelsPackage
+	TargetFile        string
+	TargetDir         string
+	Order             string
+	InitParams        map[string]interface{}
+	CustomImports     arrayImports
+	Interface         bool
+	InterfaceAndClass bool
+	Declaration       bool
+	JavaScript        bool
+	Zod               bool
+	Readonly          bool
+	AllOptional       bool
+	CamelCase         bool
+	CamelCaseOptions  CamelCaseOptions
+	CreateFromMethod  bool
+	PackageFiles      map[string]string
+	BackupDir         string
+	BackupKeep        int
+	Prefix            string
+	Suffix            string
+	Indent            string
+	InterfacePrefix   string
+	ValidateTags      bool
+	DontExport        bool
+	NoConstructor     bool
+	Strict            bool
+	ManagedTypes      []ManagedType
+	LocalPkg          bool
+	Static            bool
+	InModule          bool
+	Check             bool
+	Diff              bool
+	Snapshot          string
+	Report            string
+	UpdateSnapshot    bool
+	Verbose           bool
+	Quiet             bool
+	EmitProgram       string
 }
 
 func main() {
 	var p Params
-	var backupDir string
-	flag.StringVar(&p.ModelsPackage, "package", "", "Path of the package with models")
+	var configFile string
+	var packages arrayPackages
+	var include, exclude string
+	var annotated, watchSources bool
+	var watchInterval time.Duration
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "restore" {
+		if err := restore(args[1:]); err != nil {
+			exit(err)
+		}
+		return
+	}
+	if len(args) > 0 && args[0] == "diff" {
+		// tscriptify diff -snapshot=file.json [-report=report.json] [-update] ...
+		p.Diff = true
+		args = args[1:]
+		flag.StringVar(&p.Snapshot, "snapshot", "", "Snapshot (JSON) of the models to compare with (diff)")
+		flag.StringVar(&p.Report, "report", "", "File where the changes are saved as JSON (diff)")
+		flag.BoolVar(&p.UpdateSnapshot, "update", false, "Save the current models in the snapshot after comparing (diff)")
+	}
+	flag.StringVar(&configFile, "config", "", "Configuration file (YAML or JSON) with the targets to generate, instead of the other options")
+	flag.Var(&packages, "package", "Path of a package with models, repeat this option for each package. Structs of other packages than the first are given as alias.Struct, the alias is the last element of the path or set with -package=alias=path")
+	flag.StringVar(&include, "include", "", "Regular expression, only the structs found in Go files and directories with a matching name are converted")
+	flag.StringVar(&exclude, "exclude", "", "Regular expression, the structs found in Go files and directories with a matching name are not converted")
+	flag.BoolVar(&annotated, "annotated", false, "Only convert the structs found in Go files and directories with a "+exportAnnotation+" comment")
 	flag.StringVar(&p.TargetFile, "target", "", "Target typescript file")
-	flag.StringVar(&backupDir, "backup", "", "Directory where backup files are saved")
+	flag.StringVar(&p.TargetDir, "target-dir", "", "Target directory, with one typescript file per Go package")
+	flag.StringVar(&p.BackupDir, "backup", "", "Directory where backup files are saved")
+	flag.IntVar(&p.BackupKeep, "backup-keep", 0, "Number of backups kept per target file, 0 keeps all")
+	flag.StringVar(&p.Order, "order", string(typescriptify.OrderInsertion), "Order of declarations: insertion, topological or alphabetical")
 	flag.BoolVar(&p.Interface, "interface", false, "Create interfaces (not classes)")
+	flag.BoolVar(&p.InterfaceAndClass, "interface-and-class", false, "Create an interface and a class implementing it for every struct")
+	flag.BoolVar(&p.Declaration, "declaration", false, "Create ambient declarations only (for .d.ts files)")
+	flag.BoolVar(&p.JavaScript, "js", false, "Create plain JavaScript with JSDoc types (interfaces are created as typedefs)")
+	flag.BoolVar(&p.Zod, "zod", false, "Create interfaces with zod schemas (checking the constraints of validate tags if validateTags is set in the config file)")
 	flag.BoolVar(&p.Readonly, "readonly", false, "Set all fields readonly")
 	flag.BoolVar(&p.AllOptional, "all-optional", false, "Set all fields optional")
 	flag.BoolVar(&p.CamelCase, "camel-case", false, "Convert all field names to camelCase")
+	flag.BoolVar(&p.Strict, "strict", false, "Fail on warnings too, i.e. anonymous structs and ignored chan or func fields")
 	flag.Var(&p.CustomImports, "import", "Typescript import for your custom type, repeat this option for each import needed")
+	flag.BoolVar(&p.Check, "check", false, "Only check if the target is up to date, print a diff and exit with status 1 if not")
+	flag.BoolVar(&p.Static, "static", false, "Analyze the models package from source in the current module, without compiling a generator program")
+	flag.BoolVar(&p.InModule, "in-module", false, "Run the generator program in the current module (with its go.mod, replace directives and vendor directory), without downloading dependencies")
 	flag.BoolVar(&p.LocalPkg, "local-pkg", false, "Replace github.com/GoodNotes/typescriptify-golang-structs with the current directory in go.mod file. Useful for local development.")
-	flag.BoolVar(&p.Verbose, "verbose", false, "Verbose logs")
-	flag.Parse()
-
-	structs := []string{}
-	for _, structOrGoFile := range flag.Args() {
-		if strings.HasSuffix(structOrGoFile, ".go") {
-			fmt.Println("Parsing:", structOrGoFile)
-			fileStructs, err := GetGolangFileStructs(structOrGoFile)
+	flag.BoolVar(&p.Verbose, "verbose", false, "Verbose logs, with the conversion of every type and field and the generator program")
+	flag.BoolVar(&p.Quiet, "quiet", false, "Only log errors")
+	flag.StringVar(&p.EmitProgram, "emit-program", "", "Directory where the generator program is written (as main.go, with a go:generate line), instead of running it")
+	flag.BoolVar(&watchSources, "watch", false, "Convert again when the Go files of the models packages change, until interrupted")
+	flag.DurationVar(&watchInterval, "watch-interval", 500*time.Millisecond, "Interval between checks of the Go files with -watch, the files must not change during one interval before converting")
+	if err := flag.CommandLine.Parse(args); err != nil {
+		exit(usageError(err.Error()))
+	}
+	if p.Verbose && p.Quiet {
+		exit(usageError("-verbose can't be used with -quiet"))
+	}
+	logger = newLogger(p.Verbose, p.Quiet)
+	removeTempDirsOnInterrupt()
+
+	// targets returns the parameters of all targets, they are loaded again after changes with -watch:
+	targets := func() ([]Params, error) {
+		filter, err := newStructFilter(include, exclude, annotated)
+		if err != nil {
+			return nil, err
+		}
+		p.Packages, err = targetPackages(packages, flag.Args(), nil, "", filter)
+		return []Params{p}, err
+	}
+	// sources returns the packages and files to watch if the targets can't be loaded:
+	sources := func() ([]string, []string) {
+		return targetSources(packages, flag.Args(), "")
+	}
+	var watchFiles []string
+	if len(configFile) > 0 {
+		if len(p.EmitProgram) > 0 {
+			exit(usageError("-emit-program can't be used with -config"))
+		}
+		targets = func() ([]Params, error) {
+			config, err := LoadConfig(configFile)
 			if err != nil {
-				panic(fmt.Sprintf("Error loading/parsing golang file %s: %s", structOrGoFile, err.Error()))
+				return nil, err
+			}
+			var result []Params
+			for _, target := range config.Targets {
+				targetParams, err := config.Params(target, p)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, targetParams)
+			}
+			return result, nil
+		}
+		sources = func() (patterns, files []string) {
+			config, err := LoadConfig(configFile)
+			if err != nil {
+				return nil, nil
+			}
+			for _, target := range config.Targets {
+				targetPatterns, targetFiles := targetSources(append([]string{target.Package}, target.Packages...), target.Structs, config.dir)
+				patterns = append(patterns, targetPatterns...)
+				files = append(files, targetFiles...)
 			}
-			structs = append(structs, fileStructs...)
-		} else {
-			structs = append(structs, structOrGoFile)
+			return patterns, files
 		}
+		watchFiles = append(watchFiles, configFile)
 	}
 
-	if len(p.ModelsPackage) == 0 {
-		fmt.Fprintln(os.Stderr, "No package given")
-		os.Exit(1)
+	if watchSources {
+		watch(targets, sources, watchFiles, watchInterval)
+		return
 	}
-	if len(p.TargetFile) == 0 {
-		fmt.Fprintln(os.Stderr, "No target file")
-		os.Exit(1)
+	params, err := targets()
+	if err != nil {
+		exit(usageError(err.Error()))
+	}
+	ok := true
+	for _, targetParams := range params {
+		targetOK, err := run(targetParams)
+		if err != nil {
+			exit(err)
+		}
+		ok = targetOK && ok
+	}
+	if !ok {
+		os.Exit(exitCheckFailed)
+	}
+}
+
+// run converts the models of one target, returns false if the check (or diff) fails.
+func run(p Params) (bool, error) {
+	if p.Diff {
+		if len(p.Snapshot) == 0 {
+			return false, usageError("No snapshot file")
+		}
+	} else if len(p.TargetFile) == 0 && len(p.TargetDir) == 0 {
+		return false, usageError("No target file")
+	}
+	// The files are written (or compared) in place, not in the temporary directory:
+	for _, path := range []*string{&p.Snapshot, &p.Report, &p.TargetDir, &p.TargetFile, &p.BackupDir} {
+		if len(*path) > 0 {
+			abs, err := filepath.Abs(*path)
+			if err != nil {
+				return false, err
+			}
+			*path = abs
+		}
+	}
+
+	if len(p.EmitProgram) > 0 {
+		return true, emitProgram(p)
+	}
+	if p.Static {
+		return runStatic(p)
 	}
 
 	t := template.Must(template.New("").Parse(TEMPLATE))
 
-	d, err := os.MkdirTemp("", "tscriptify")
-	handleErr(err)
-	defer os.RemoveAll(d)
+	d, runDir, pkg, err := programDir(p.InModule)
+	if err != nil {
+		return false, err
+	}
+	defer removeTempDir(d)
 
 	f, err := os.CreateTemp(d, "main*.go")
-	handleErr(err)
+	if err != nil {
+		return false, err
+	}
 	defer f.Close()
 
-	structsArr := make([]string, 0)
-	for _, str := range structs {
-		str = strings.TrimSpace(str)
-		if len(str) > 0 {
-			structsArr = append(structsArr, str)
+	p.InitParams = initParams(p)
+	if err := t.Execute(f, p); err != nil {
+		return false, err
+	}
+
+	if p.Verbose {
+		byts, err := os.ReadFile(f.Name())
+		if err != nil {
+			return false, err
+		}
+		logger.Debug("Compiling generated code", slog.String("file", f.Name()), slog.String("code", string(byts)))
+	}
+	if !p.InModule {
+		if _, err := executeCommand(d, nil, "go", "mod", "init", "tmp"); err != nil {
+			return false, err
+		}
+
+		if p.LocalPkg {
+			// replace github.com/GoodNotes/typescriptify-golang-structs with the current directory
+			pwd, err := os.Getwd()
+			if err != nil {
+				return false, err
+			}
+			if _, err := executeCommand(d, nil, "go", "mod", "edit", "-replace", "github.com/GoodNotes/typescriptify-golang-structs="+pwd); err != nil {
+				return false, err
+			}
+		}
+
+		cmdGet := []string{"go", "get", "-v"}
+		environ := append(os.Environ(), "GO111MODULE=on")
+		if _, err := executeCommand(d, environ, cmdGet...); err != nil {
+			return false, err
 		}
 	}
 
-	p.Structs = structsArr
-	p.InitParams = map[string]interface{}{
-		"BackupDir": fmt.Sprintf(`"%s"`, backupDir),
+	// Built and run (not with `go run`) to get the exit code of the program:
+	generator := filepath.Join(d, "generator")
+	if runtime.GOOS == "windows" {
+		generator += ".exe"
+	}
+	if _, err := executeCommand(runDir, nil, "go", "build", "-o", generator, pkg); err != nil {
+		return false, err
+	}
+	cmd := exec.Command(generator)
+	cmd.Dir = runDir
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == exitCheckFailed {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("generator program failed: %w", err)
 	}
-	err = t.Execute(f, p)
-	handleErr(err)
+	return true, nil
+}
 
-	if p.Verbose {
-		byts, err := os.ReadFile(f.Name())
-		handleErr(err)
-		fmt.Printf("\nCompiling generated code (%s):\n%s\n----------------------------------------------------------------------------------------------------\n", f.Name(), string(byts))
+func initParams(p Params) map[string]interface{} {
+	return map[string]interface{}{
+		"BackupDir":  fmt.Sprintf("%q", p.BackupDir),
+		"BackupKeep": p.BackupKeep,
+	}
+}
+
+// emitProgram writes the generator program to the EmitProgram directory, formatted and with paths relative to
+// the directory, so that it can be committed and run with `go generate`.
+func emitProgram(p Params) error {
+	dir, err := filepath.Abs(p.EmitProgram)
+	if err != nil {
+		return err
+	}
+	for _, path := range []*string{&p.TargetFile, &p.TargetDir, &p.BackupDir, &p.Snapshot, &p.Report} {
+		if len(*path) > 0 {
+			if *path, err = filepath.Rel(dir, *path); err != nil {
+				return err
+			}
+		}
 	}
-	executeCommand(d, nil, "go", "mod", "init", "tmp")
+	p.InitParams = initParams(p)
 
-	if p.LocalPkg {
-		// replace github.com/GoodNotes/typescriptify-golang-structs with the current directory
-		pwd, err := os.Getwd()
-		handleErr(err)
-		executeCommand(d, nil, "go", "mod", "edit", "-replace", "github.com/GoodNotes/typescriptify-golang-structs="+pwd)
+	var code bytes.Buffer
+	if err := template.Must(template.New("").Parse(TEMPLATE)).Execute(&code, p); err != nil {
+		return err
+	}
+	formatted, err := format.Source(code.Bytes())
+	if err != nil {
+		return err
 	}
 
-	cmdGet := []string{"go", "get", "-v"}
-	environ := append(os.Environ(), "GO111MODULE=on")
-	executeCommand(d, environ, cmdGet...)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	fileName := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(fileName, formatted, 0644); err != nil {
+		return err
+	}
+	logger.Info("Generator program written", slog.String("file", fileName))
+	return nil
+}
 
-	executeCommand(d, nil, "go", "run", ".")
+// programDir creates the temporary directory d of the generator program, which is run from runDir as pkg: in a
+// temporary module, or in the current module with -in-module. The directory is removed on interrupt, see
+// removeTempDirsOnInterrupt.
+func programDir(inModule bool) (d, runDir, pkg string, err error) {
+	if inModule {
+		if runDir, err = moduleRoot("."); err != nil {
+			return "", "", "", err
+		}
+		// Directories starting with "." are ignored by `./...` patterns while the program exists:
+		d, err = os.MkdirTemp(runDir, ".tscriptify-")
+		pkg = "./" + filepath.Base(d)
+	} else {
+		d, err = os.MkdirTemp("", "tscriptify")
+		runDir, pkg = d, "."
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+	tempDirs.Store(d, true)
+	return d, runDir, pkg, nil
+}
+
+// tempDirs are the temporary directories of the generator programs being run.
+var tempDirs sync.Map
+
+func removeTempDir(d string) {
+	os.RemoveAll(d)
+	tempDirs.Delete(d)
+}
+
+// removeTempDirsOnInterrupt removes the temporary directories when tscriptify is interrupted (i.e. with Ctrl-C
+// in watch mode), so that no program is left in the module with -in-module.
+func removeTempDirsOnInterrupt() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		removeTempDirs()
+		os.Exit(exitInterrupted)
+	}()
+}
+
+func removeTempDirs() {
+	tempDirs.Range(func(d, _ any) bool {
+		removeTempDir(d.(string))
+		return true
+	})
+}
 
-	err = os.Rename(filepath.Join(d, p.TargetFile), p.TargetFile)
-	handleErr(err)
+// moduleRoot returns the root directory of the module of a directory.
+func moduleRoot(dir string) (string, error) {
+	cmd := exec.Command("go", "env", "GOMOD")
+	cmd.Dir = dir
+	gomod, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	fileName := strings.TrimSpace(string(gomod))
+	if fileName == "" || fileName == os.DevNull {
+		return "", usageError("Not in a module, -in-module needs a go.mod file (in the current directory or a parent)")
+	}
+	return filepath.Dir(fileName), nil
 }
 
 // cmdDir: Directory to execute command from
 // env: Environment variables (Optional, Pass nil if not required)
 // args: Command arguments
-func executeCommand(cmdDir string, env []string, args ...string) string {
+func executeCommand(cmdDir string, env []string, args ...string) (string, error) {
 	cmd := exec.Command(args[0], args[1:]...)
 
 	// Assign environment variables, if provided.
@@ -175,57 +586,77 @@ func executeCommand(cmdDir string, env []string, args ...string) string {
 		cmd.Env = env
 	}
 
-	fmt.Println(cmdDir + ": " + strings.Join(cmd.Args, " "))
+	logger.Debug("Running command", slog.String("dir", cmdDir), slog.String("command", strings.Join(cmd.Args, " ")))
 	cmd.Dir = cmdDir
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Println(string(output))
-		handleErr(err)
+		logger.Error("Command failed", slog.String("command", strings.Join(cmd.Args, " ")), slog.String("output", string(output)))
+		return "", err
 	}
-	fmt.Println(string(output))
+	logger.Debug("Command output", slog.String("command", strings.Join(cmd.Args, " ")), slog.String("output", string(output)))
 
-	return string(output)
+	return string(output), nil
 }
 
-func GetGolangFileStructs(filename string) ([]string, error) {
-	fset := token.NewFileSet() // positions are relative to fset
+// restore lists the backups of a target file, or restores one of them:
+//
+//	tscriptify restore -backup=dir -target=file [-n=1]
+func restore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	backupDir := flags.String("backup", ".", "Directory where backup files are saved")
+	targetFile := flags.String("target", "", "Target typescript file")
+	n := flags.Int("n", 0, "Number of the backup to restore (1 is the newest), if 0 the backups are listed")
+	if err := flags.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	if len(*targetFile) == 0 {
+		return usageError("No target file")
+	}
 
-	f, err := parser.ParseFile(fset, filename, nil, 0)
+	t := typescriptify.New().WithBackupDir(*backupDir)
+	backups, err := t.Backups(*targetFile)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	v := &AVisitor{}
-	ast.Walk(v, f)
-
-	return v.structs, nil
+	if *n == 0 {
+		for i, backup := range backups {
+			fmt.Printf("%3d  %s  %s\n", i+1, backup.Time.Format("2006-01-02 15:04:05"), backup.FileName)
+		}
+		return nil
+	}
+	if *n < 1 || *n > len(backups) {
+		return usageError(fmt.Sprintf("No backup %d of %s (found %d)", *n, *targetFile, len(backups)))
+	}
+	if err := t.RestoreBackup(*targetFile, backups[*n-1]); err != nil {
+		return err
+	}
+	fmt.Println("Restored", backups[*n-1].FileName)
+	return nil
 }
 
-type AVisitor struct {
-	structNameCandidate string
-	structs             []string
-}
+// Exit codes of tscriptify (and of the generator program)
+const (
+	exitCheckFailed = 1   // The target isn't up to date (-check), or there are breaking changes (diff)
+	exitUsage       = 2   // Invalid options or configuration
+	exitError       = 3   // The conversion (or another operation) failed
+	exitInterrupted = 130 // Interrupted (with SIGINT or SIGTERM), the temporary directories are removed
+)
 
-func (v *AVisitor) Visit(node ast.Node) ast.Visitor {
-	if node != nil {
-		switch t := node.(type) {
-		case *ast.Ident:
-			v.structNameCandidate = t.Name
-		case *ast.StructType:
-			if len(v.structNameCandidate) > 0 {
-				v.structs = append(v.structs, v.structNameCandidate)
-				v.structNameCandidate = ""
-			}
-		default:
-			v.structNameCandidate = ""
-		}
-	}
-	return v
+// usageError is an error of the options or configuration, reported without prefix (see exit).
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
 }
 
-func handleErr(err error) {
-	if err != nil {
-		panic(err.Error())
+// exit reports an error and exits, with exitUsage for usage errors or exitError.
+func exit(err error) {
+	var usage usageError
+	if errors.As(err, &usage) {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(exitUsage)
 	}
+	fmt.Fprintln(os.Stderr, "Error:", err.Error())
+	os.Exit(exitError)
 }
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmitProgram(t *testing.T) {
+	t.Parallel()
+
+	packages := []ModelsPackage{
+		{Path: "github.com/GoodNotes/typescriptify-golang-structs/example/example-models", Alias: "models", Structs: []string{"Person", "Address"}},
+		{Path: "example.com/billing", Alias: "billing", Structs: []string{"Invoice"}, Enums: []string{"AllStatuses"}},
+	}
+	for _, test := range []struct {
+		name     string
+		params   func(dir string) Params
+		expected []string
+	}{
+		{
+			name: "file",
+			params: func(dir string) Params {
+				return Params{TargetFile: filepath.Join(dir, "ts", "models.ts"), BackupDir: filepath.Join(dir, "ts", "backups"), BackupKeep: 3}
+			},
+			expected: []string{
+				`t.ConvertToFile("../../ts/models.ts")`,
+				`t.BackupDir = "../../ts/backups"`,
+				"t.BackupKeep = 3",
+				`t.Add(models.Person{})`,
+				`t.Add(billing.Invoice{})`,
+				`t.AddEnum(billing.AllStatuses)`,
+				`t.Logger.Info("Converted", slog.String("target", "../../ts/models.ts"))`,
+			},
+		},
+		{
+			name: "directory check",
+			params: func(dir string) Params {
+				return Params{
+					TargetDir:        filepath.Join(dir, "ts"),
+					Check:            true,
+					AllOptional:      true,
+					Interface:        true,
+					CamelCase:        true,
+					CamelCaseOptions: CamelCaseOptions{PreserveConsecutiveUppercase: true},
+					CreateFromMethod: true,
+					PackageFiles:     map[string]string{"example.com/billing": "invoices"},
+				}
+			},
+			expected: []string{
+				`err := t.CheckDir("../../ts")`,
+				`t.BackupDir = ""`,
+				`t.AddTypeWithName(modelsPersonOptional, "Person")`,
+				`modelsPersonOptional := optional(reflect.TypeOf(models.Person{}))`,
+				`result, err := typescriptify.TryTagAll(typ, []string{"omitempty"})`,
+				"t.CreateInterface = true",
+				"t.CamelCaseOptions = &typescriptify.CamelCaseOptions{PreserveConsecutiveUppercase: true}",
+				"t.CreateFromMethod = true",
+				`t.WithPackageFile("example.com/billing", "invoices")`,
+				`t.Logger.Info("Up to date", slog.String("target", "../../ts"))`,
+			},
+		},
+		{
+			name: "diff",
+			params: func(dir string) Params {
+				return Params{Diff: true, Snapshot: filepath.Join(dir, "ts", "models.snapshot.json"), Report: filepath.Join(dir, "report.json"), UpdateSnapshot: true}
+			},
+			expected: []string{
+				`t.Diff("../../ts/models.snapshot.json")`,
+				`os.WriteFile("../../report.json", byts, 0644)`,
+				`t.SaveSnapshot("../../ts/models.snapshot.json")`,
+				`t.Logger.Info("No breaking changes", slog.String("snapshot", "../../ts/models.snapshot.json"))`,
+			},
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			p := test.params(dir)
+			p.Packages = packages
+			p.Order = "insertion"
+			p.EmitProgram = filepath.Join(dir, "tools", "tsgen")
+			assert.Nil(t, emitProgram(p))
+
+			byts, err := os.ReadFile(filepath.Join(dir, "tools", "tsgen", "main.go"))
+			assert.Nil(t, err)
+			code := string(byts)
+			assert.True(t, strings.HasPrefix(code, "//go:generate go run .\n"), code)
+			formatted, err := format.Source(byts)
+			assert.Nil(t, err)
+			assert.Equal(t, string(formatted), code, "not gofmt-clean")
+			assert.NotContains(t, code, dir)
+			assert.NotContains(t, code, `fmt.Println("OK")`)
+			for _, expected := range test.expected {
+				assert.Contains(t, code, expected)
+			}
+		})
+	}
+}
+
+func TestModuleRoot(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "module", "go.mod"), "module example.com\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "module", "models", "models.go"), "package models\n")
+
+	root, err := moduleRoot(filepath.Join(dir, "module", "models"))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "module"), root)
+
+	_, err = moduleRoot(dir)
+	var usage usageError
+	assert.True(t, errors.As(err, &usage), "%v", err)
+}
+
+// The tests creating programs in this module are not parallel, so that no other test sees their directories.
+
+func TestProgramDirInModule(t *testing.T) {
+	root, err := moduleRoot(".")
+	assert.Nil(t, err)
+
+	d, runDir, pkg, err := programDir(true)
+	assert.Nil(t, err)
+	assert.Equal(t, root, runDir)
+	assert.Equal(t, root, filepath.Dir(d))
+	assert.True(t, strings.HasPrefix(filepath.Base(d), ".tscriptify-"), d)
+	assert.Equal(t, "./"+filepath.Base(d), pkg)
+
+	// Removed when interrupted:
+	removeTempDirs()
+	_, err = os.Stat(d)
+	assert.True(t, os.IsNotExist(err), "%v", err)
+}
+
+func TestRunInModule(t *testing.T) {
+	dir := t.TempDir()
+	ok, err := run(Params{
+		InModule:   true,
+		TargetFile: filepath.Join(dir, "models.ts"),
+		Order:      "insertion",
+		Interface:  true,
+		Packages:   []ModelsPackage{{Path: "github.com/GoodNotes/typescriptify-golang-structs/example/example-models", Alias: "models", Structs: []string{"Address"}}},
+	})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	byts, err := os.ReadFile(filepath.Join(dir, "models.ts"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), "export interface Address {")
+
+	// The program is removed from the module:
+	root, err := moduleRoot(".")
+	assert.Nil(t, err)
+	programs, err := filepath.Glob(filepath.Join(root, ".tscriptify-*"))
+	assert.Nil(t, err)
+	assert.Empty(t, programs)
+}
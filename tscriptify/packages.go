@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// ModelsPackage is a package with models, imported with Alias in the generator program.
+type ModelsPackage struct {
+	Path    string
+	Alias   string
+	Structs []string
+	Enums   []string
+}
+
+// reservedAliases are the names used in the generator program (see TEMPLATE).
+var reservedAliases = map[string]bool{
+	"errors": true, "fmt": true, "json": true, "os": true, "reflect": true, "typescriptify": true,
+	"slog": true, "t": true, "err": true, "report": true, "byts": true, "drift": true, "fail": true,
+	"optional": true, "withoutTime": true,
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// targetPackages returns the packages of a target with their structs (given as arguments, see selectStructs)
+// and enums.
+func targetPackages(paths, args, enums []string, dir string, filter structFilter) ([]ModelsPackage, error) {
+	structs, found, err := selectStructs(args, dir, filter)
+	if err != nil {
+		return nil, err
+	}
+	return modelsPackages(paths, structs, enums, found)
+}
+
+// modelsPackages returns the packages given as `path` or `alias=path`, with their structs and enums, and the
+// packages found in Go files and directories. Names qualified with an alias (`alias.Name`) belong to that package, the
+// others to the first package.
+func modelsPackages(paths, structs, enums []string, found []ModelsPackage) ([]ModelsPackage, error) {
+	var result []ModelsPackage
+	aliases := map[string]int{}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		var alias string
+		if i := strings.Index(path, "="); i >= 0 {
+			alias, path = path[:i], path[i+1:]
+			if !token.IsIdentifier(alias) {
+				return nil, fmt.Errorf("invalid package alias %q", alias)
+			}
+		} else {
+			alias = packageAlias(path)
+		}
+		if len(path) == 0 {
+			return nil, fmt.Errorf("no path for package alias %q", alias)
+		}
+		if reservedAliases[alias] {
+			return nil, fmt.Errorf("package alias %q is used in the generator program, set another one with -package=alias=%s", alias, path)
+		}
+		if _, found := aliases[alias]; found {
+			return nil, fmt.Errorf("duplicate package alias %q, set another one with -package=alias=%s", alias, path)
+		}
+		aliases[alias] = len(result)
+		result = append(result, ModelsPackage{Path: path, Alias: alias})
+	}
+	for _, foundPackage := range found {
+		i := indexOfPackage(result, foundPackage.Path)
+		if i < 0 {
+			// Found packages get a unique alias:
+			alias := packageAlias(foundPackage.Path)
+			for n := 2; aliasTaken(aliases, alias); n++ {
+				alias = fmt.Sprintf("%s%d", packageAlias(foundPackage.Path), n)
+			}
+			i = len(result)
+			aliases[alias] = i
+			result = append(result, ModelsPackage{Path: foundPackage.Path, Alias: alias})
+		}
+		result[i].addStructs(foundPackage.Structs...)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no package given")
+	}
+
+	pkg := func(name string) (*ModelsPackage, string, error) {
+		dot := strings.LastIndex(name, ".")
+		if dot < 0 {
+			return &result[0], name, nil
+		}
+		i, found := aliases[name[:dot]]
+		if !found {
+			return nil, "", fmt.Errorf("unknown package alias %q in %s", name[:dot], name)
+		}
+		return &result[i], name[dot+1:], nil
+	}
+	for _, name := range structs {
+		p, name, err := pkg(name)
+		if err != nil {
+			return nil, err
+		}
+		p.addStructs(name)
+	}
+	for _, name := range enums {
+		p, name, err := pkg(name)
+		if err != nil {
+			return nil, err
+		}
+		p.Enums = append(p.Enums, name)
+	}
+	return result, nil
+}
+
+// addStructs adds the structs not added yet.
+func (p *ModelsPackage) addStructs(names ...string) {
+	for _, name := range names {
+		if !slices.Contains(p.Structs, name) {
+			p.Structs = append(p.Structs, name)
+		}
+	}
+}
+
+func aliasTaken(aliases map[string]int, alias string) bool {
+	_, found := aliases[alias]
+	return found || reservedAliases[alias]
+}
+
+func indexOfPackage(packages []ModelsPackage, path string) int {
+	for i, pkg := range packages {
+		if pkg.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// packageAlias returns the default alias of a package: the last element of its path (without a major
+// version suffix), as a Go identifier.
+func packageAlias(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	alias := parts[len(parts)-1]
+	if len(parts) > 1 && majorVersion.MatchString(alias) {
+		alias = parts[len(parts)-2]
+	}
+	alias = strings.Map(func(r rune) rune {
+		if r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, alias)
+	if len(alias) == 0 || '0' <= alias[0] && alias[0] <= '9' {
+		alias = "_" + alias
+	}
+	return alias
+}
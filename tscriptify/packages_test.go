@@ -0,0 +1,134 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageAlias(t *testing.T) {
+	t.Parallel()
+
+	for path, expected := range map[string]string{
+		"example.com/models":         "models",
+		"example.com/models/":        "models",
+		"example.com/models/v2":      "models",
+		"example.com/v2":             "example_com",
+		"v2":                         "v2",
+		"example.com/api/v10":        "api",
+		"example.com/example-models": "example_models",
+		"example.com/go.models":      "go_models",
+		"example.com/2fa":            "_2fa",
+		"models":                     "models",
+		"example.com/vX":             "vX",
+	} {
+		assert.Equal(t, expected, packageAlias(path), path)
+	}
+}
+
+func TestModelsPackages(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		paths    []string
+		structs  []string
+		enums    []string
+		found    []ModelsPackage
+		expected []ModelsPackage
+		err      string
+	}{
+		{
+			name:     "default alias",
+			paths:    []string{"example.com/models"},
+			structs:  []string{"Person", "Address", "Person"},
+			enums:    []string{"AllColors"},
+			expected: []ModelsPackage{{Path: "example.com/models", Alias: "models", Structs: []string{"Person", "Address"}, Enums: []string{"AllColors"}}},
+		},
+		{
+			name:    "qualified names",
+			paths:   []string{"example.com/models", "example.com/billing/v2", " acme=example.com/other "},
+			structs: []string{"Person", "billing.Invoice", "acme.Item"},
+			enums:   []string{"acme.AllKinds"},
+			expected: []ModelsPackage{
+				{Path: "example.com/models", Alias: "models", Structs: []string{"Person"}},
+				{Path: "example.com/billing/v2", Alias: "billing", Structs: []string{"Invoice"}},
+				{Path: "example.com/other", Alias: "acme", Structs: []string{"Item"}, Enums: []string{"AllKinds"}},
+			},
+		},
+		{
+			name:  "unique aliases of found packages",
+			paths: []string{"example.com/models"},
+			found: []ModelsPackage{
+				{Path: "example.com/models", Structs: []string{"Person"}},
+				{Path: "example.com/third/models", Structs: []string{"Item"}},
+				{Path: "example.com/fourth/models/v3", Structs: []string{"Order"}},
+				{Path: "example.com/fmt", Structs: []string{"Format"}},
+			},
+			structs: []string{"Address", "models2.Tag"},
+			expected: []ModelsPackage{
+				{Path: "example.com/models", Alias: "models", Structs: []string{"Person", "Address"}},
+				{Path: "example.com/third/models", Alias: "models2", Structs: []string{"Item", "Tag"}},
+				{Path: "example.com/fourth/models/v3", Alias: "models3", Structs: []string{"Order"}},
+				{Path: "example.com/fmt", Alias: "fmt2", Structs: []string{"Format"}},
+			},
+		},
+		{
+			name: "only found packages",
+			found: []ModelsPackage{
+				{Path: "example.com/models", Structs: []string{"Person"}},
+			},
+			expected: []ModelsPackage{{Path: "example.com/models", Alias: "models", Structs: []string{"Person"}}},
+		},
+		{
+			name:  "duplicate alias",
+			paths: []string{"example.com/models", "example.com/models/v2"},
+			err:   `duplicate package alias "models", set another one with -package=alias=example.com/models/v2`,
+		},
+		{
+			name:  "reserved alias",
+			paths: []string{"example.com/models", "example.com/reflect"},
+			err:   `package alias "reflect" is used in the generator program`,
+		},
+		{
+			name:  "reserved explicit alias",
+			paths: []string{"t=example.com/models"},
+			err:   `package alias "t" is used in the generator program`,
+		},
+		{
+			name:  "invalid alias",
+			paths: []string{"my-models=example.com/models"},
+			err:   `invalid package alias "my-models"`,
+		},
+		{
+			name:  "no path",
+			paths: []string{"models="},
+			err:   `no path for package alias "models"`,
+		},
+		{
+			name:    "unknown alias",
+			paths:   []string{"example.com/models"},
+			structs: []string{"billing.Invoice"},
+			err:     `unknown package alias "billing" in billing.Invoice`,
+		},
+		{
+			name: "no package",
+			err:  "no package given",
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			packages, err := modelsPackages(test.paths, test.structs, test.enums, test.found)
+			if test.err != "" {
+				assert.NotNil(t, err)
+				if err != nil {
+					assert.Contains(t, err.Error(), test.err)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, packages)
+		})
+	}
+}
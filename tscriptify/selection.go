@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// exportAnnotation marks the structs selected with -annotated.
+const exportAnnotation = "//tscriptify:export"
+
+// structFilter selects the structs found in Go files and directories (see -include, -exclude and -annotated).
+type structFilter struct {
+	Include   *regexp.Regexp
+	Exclude   *regexp.Regexp
+	Annotated bool // Only structs with a //tscriptify:export comment
+}
+
+func newStructFilter(include, exclude string, annotated bool) (structFilter, error) {
+	filter := structFilter{Annotated: annotated}
+	var err error
+	if len(include) > 0 {
+		if filter.Include, err = regexp.Compile(include); err != nil {
+			return filter, fmt.Errorf("invalid include pattern: %w", err)
+		}
+	}
+	if len(exclude) > 0 {
+		if filter.Exclude, err = regexp.Compile(exclude); err != nil {
+			return filter, fmt.Errorf("invalid exclude pattern: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+func (f structFilter) match(name string, annotated bool) bool {
+	if f.Annotated && !annotated {
+		return false
+	}
+	if f.Include != nil && !f.Include.MatchString(name) {
+		return false
+	}
+	return f.Exclude == nil || !f.Exclude.MatchString(name)
+}
+
+// selectStructs returns the struct names given as arguments, and the packages of the structs found in Go files
+// (or globs like `models/*.go`) and in package directories (like `./models` or `./models/...`). Paths are
+// relative to dir (or the current directory if empty).
+func selectStructs(args []string, dir string, filter structFilter) ([]string, []ModelsPackage, error) {
+	structs := []string{}
+	var packages []ModelsPackage
+	dirPackages := map[string]string{} // Import paths of the directories of Go files
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		switch {
+		case len(arg) == 0:
+		case strings.HasSuffix(arg, ".go"):
+			if len(dir) > 0 && !filepath.IsAbs(arg) {
+				arg = filepath.Join(dir, arg)
+			}
+			fileNames, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid pattern %s: %w", arg, err)
+			}
+			if len(fileNames) == 0 {
+				return nil, nil, fmt.Errorf("no Go files match %s", arg)
+			}
+			for _, fileName := range fileNames {
+				logger.Info("Parsing", slog.String("file", fileName))
+				fileStructs, err := golangFileStructs(fileName, filter)
+				if err != nil {
+					return nil, nil, fmt.Errorf("error loading/parsing golang file %s: %w", fileName, err)
+				}
+				if len(fileStructs) == 0 {
+					continue
+				}
+				fileDir := filepath.Dir(fileName)
+				pkgPath, found := dirPackages[fileDir]
+				if !found {
+					if pkgPath, err = directoryPackage(fileDir); err != nil {
+						return nil, nil, fmt.Errorf("error finding the package of %s: %w", fileName, err)
+					}
+					dirPackages[fileDir] = pkgPath
+				}
+				packages = append(packages, ModelsPackage{Path: pkgPath, Structs: fileStructs})
+			}
+		case strings.HasPrefix(arg, ".") || strings.ContainsRune(arg, '/') || filepath.IsAbs(arg):
+			found, err := packageStructs(arg, dir, filter)
+			if err != nil {
+				return nil, nil, err
+			}
+			packages = append(packages, found...)
+		default:
+			structs = append(structs, arg)
+		}
+	}
+	return structs, packages, nil
+}
+
+// packageStructs returns the packages matching a `go list` pattern, with their structs.
+func packageStructs(pattern, dir string, filter structFilter) ([]ModelsPackage, error) {
+	cmd := exec.Command("go", "list", "-find", "-f", `{{.ImportPath}}{{"\t"}}{{.Dir}}{{range .GoFiles}}{{"\t"}}{{.}}{{end}}`, pattern)
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing packages %s: %w", pattern, err)
+	}
+	var packages []ModelsPackage
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 3 {
+			continue
+		}
+		logger.Info("Parsing package", slog.String("package", parts[0]))
+		pkg := ModelsPackage{Path: parts[0]}
+		for _, fileName := range parts[2:] {
+			fileStructs, err := golangFileStructs(filepath.Join(parts[1], fileName), filter)
+			if err != nil {
+				return nil, fmt.Errorf("error loading/parsing golang file %s: %w", fileName, err)
+			}
+			pkg.Structs = append(pkg.Structs, fileStructs...)
+		}
+		if len(pkg.Structs) > 0 {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages, nil
+}
+
+// directoryPackage returns the import path of the package in a directory.
+func directoryPackage(dir string) (string, error) {
+	cmd := exec.Command("go", "list", "-find", "-f", "{{.ImportPath}}", ".")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetGolangFileStructs returns the names of the exported structs declared in a Go file.
+func GetGolangFileStructs(filename string) ([]string, error) {
+	return golangFileStructs(filename, structFilter{})
+}
+
+// golangFileStructs returns the names of the exported structs declared in a Go file which match the filter.
+// Test files are skipped, generic structs (which can't be converted without type arguments) too.
+func golangFileStructs(fileName string, filter structFilter) ([]string, error) {
+	if strings.HasSuffix(fileName, "_test.go") {
+		return nil, nil
+	}
+	fset := token.NewFileSet() // positions are relative to fset
+
+	f, err := parser.ParseFile(fset, fileName, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var structs []string
+	for _, decl := range f.Decls {
+		genDecl, is := decl.(*ast.GenDecl)
+		if !is || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if _, is := typeSpec.Type.(*ast.StructType); !is || !typeSpec.Name.IsExported() || typeSpec.Assign.IsValid() {
+				continue
+			}
+			// The annotation of a `type ( ... )` group is valid for all its types:
+			annotated := hasAnnotation(typeSpec.Doc) || hasAnnotation(genDecl.Doc)
+			if !filter.match(typeSpec.Name.Name, annotated) {
+				continue
+			}
+			if typeSpec.TypeParams != nil {
+				logger.Warn("Skipping generic struct", slog.String("struct", typeSpec.Name.Name))
+				continue
+			}
+			structs = append(structs, typeSpec.Name.Name)
+		}
+	}
+	return structs, nil
+}
+
+func hasAnnotation(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(comment.Text) == exportAnnotation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const modelsSource = `package models
+
+//tscriptify:export
+type Person struct {
+	Name string
+}
+
+type Address struct {
+	City string
+}
+
+type (
+	// Invoice is declared in a group.
+	Invoice struct{}
+	Item    struct{}
+	Total   = Invoice
+	Amount  int
+)
+
+//tscriptify:export
+type (
+	Order    struct{}
+	internal struct{}
+)
+
+type (
+	//tscriptify:export
+	Page[T any] struct {
+		Items []T
+	}
+	//tscriptify:export
+	Tag struct{}
+)
+
+type private struct{}
+
+func (Person) Method() {}
+`
+
+func writeFile(t *testing.T, fileName, content string) {
+	assert.Nil(t, os.MkdirAll(filepath.Dir(fileName), 0755))
+	assert.Nil(t, os.WriteFile(fileName, []byte(content), 0644))
+}
+
+func TestGolangFileStructs(t *testing.T) {
+	t.Parallel()
+	fileName := filepath.Join(t.TempDir(), "models.go")
+	writeFile(t, fileName, modelsSource)
+
+	for _, test := range []struct {
+		name     string
+		filter   structFilter
+		expected []string
+	}{
+		{name: "all", expected: []string{"Person", "Address", "Invoice", "Item", "Order", "Tag"}},
+		{name: "annotated", filter: structFilter{Annotated: true}, expected: []string{"Person", "Order", "Tag"}},
+		{name: "include", filter: mustStructFilter(t, "^(Person|Item|Page)$", "", false), expected: []string{"Person", "Item"}},
+		{name: "exclude", filter: mustStructFilter(t, "", "^I", false), expected: []string{"Person", "Address", "Order", "Tag"}},
+		{name: "annotated and exclude", filter: mustStructFilter(t, "", "Order", true), expected: []string{"Person", "Tag"}},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			structs, err := golangFileStructs(fileName, test.filter)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, structs)
+		})
+	}
+}
+
+func TestGolangFileStructsTestFile(t *testing.T) {
+	t.Parallel()
+	fileName := filepath.Join(t.TempDir(), "models_test.go")
+	writeFile(t, fileName, modelsSource)
+
+	structs, err := golangFileStructs(fileName, structFilter{})
+	assert.Nil(t, err)
+	assert.Empty(t, structs)
+}
+
+func TestGolangFileStructsInvalid(t *testing.T) {
+	t.Parallel()
+	fileName := filepath.Join(t.TempDir(), "models.go")
+	writeFile(t, fileName, "package models\n\ntype Person struct {\n")
+
+	_, err := golangFileStructs(fileName, structFilter{})
+	assert.NotNil(t, err)
+}
+
+func TestStructFilter(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		include, exclude string
+		annotatedOnly    bool
+		name             string
+		annotated        bool
+		expected         bool
+	}{
+		{name: "Person", expected: true},
+		{include: "^P", name: "Person", expected: true},
+		{include: "^P", name: "Address", expected: false},
+		{exclude: "Request$", name: "Person", expected: true},
+		{exclude: "Request$", name: "LoginRequest", expected: false},
+		{include: "^Login", exclude: "Request$", name: "LoginRequest", expected: false},
+		{include: "^Login", exclude: "Request$", name: "LoginResponse", expected: true},
+		{annotatedOnly: true, name: "Person", expected: false},
+		{annotatedOnly: true, name: "Person", annotated: true, expected: true},
+		{annotatedOnly: true, include: "^A", name: "Person", annotated: true, expected: false},
+		{name: "Person", annotated: true, expected: true},
+	} {
+		filter := mustStructFilter(t, test.include, test.exclude, test.annotatedOnly)
+		assert.Equal(t, test.expected, filter.match(test.name, test.annotated), "%+v", test)
+	}
+}
+
+func TestStructFilterInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := newStructFilter("(", "", false)
+	assert.NotNil(t, err)
+	_, err = newStructFilter("", "[", false)
+	assert.NotNil(t, err)
+}
+
+func TestSelectStructs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "models", "models.go"), "package models\n\ntype Person struct{}\n")
+	writeFile(t, filepath.Join(dir, "models", "address.go"), "package models\n\ntype Address struct{}\n")
+	writeFile(t, filepath.Join(dir, "billing", "billing.go"), "package billing\n\ntype Invoice struct{}\n\ntype Item struct{}\n")
+
+	structs, packages, err := selectStructs([]string{"Order", "models/*.go", "billing/billing.go", " ", "other.Tag"}, dir, structFilter{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Order", "other.Tag"}, structs)
+	assert.Equal(t, []ModelsPackage{
+		{Path: "example.com/models", Structs: []string{"Address"}},
+		{Path: "example.com/models", Structs: []string{"Person"}},
+		{Path: "example.com/billing", Structs: []string{"Invoice", "Item"}},
+	}, packages)
+
+	// The structs of files are qualified with the alias of their package:
+	result, err := modelsPackages([]string{"example.com/billing", "example.com/other"}, structs, nil, packages)
+	assert.Nil(t, err)
+	assert.Equal(t, []ModelsPackage{
+		{Path: "example.com/billing", Alias: "billing", Structs: []string{"Invoice", "Item", "Order"}},
+		{Path: "example.com/other", Alias: "other", Structs: []string{"Tag"}},
+		{Path: "example.com/models", Alias: "models", Structs: []string{"Address", "Person"}},
+	}, result)
+
+	_, _, err = selectStructs([]string{"missing/*.go"}, dir, structFilter{})
+	assert.NotNil(t, err)
+}
+
+func mustStructFilter(t *testing.T, include, exclude string, annotated bool) structFilter {
+	filter, err := newStructFilter(include, exclude, annotated)
+	assert.Nil(t, err)
+	return filter
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify"
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/static"
+)
+
+// runStatic converts the models like the generator program (see TEMPLATE), but the models package is
+// analyzed from source (see static.AddPackage) so nothing is compiled. Returns false if the check
+// (or diff) fails.
+func runStatic(p Params) (bool, error) {
+	t := typescriptify.New()
+	t.Logger = logger
+	t.CreateInterface = p.Interface
+	t.Declaration = p.Declaration
+	t.JavaScript = p.JavaScript
+	t.Zod = p.Zod
+	t.InterfaceAndClass = p.InterfaceAndClass
+	t.ReadOnlyFields = p.Readonly
+	t.CamelCaseFields = p.CamelCase
+	if p.CamelCaseOptions.PreserveConsecutiveUppercase {
+		t.CamelCaseOptions = &typescriptify.CamelCaseOptions{PreserveConsecutiveUppercase: true}
+	}
+	t.CreateFromMethod = p.CreateFromMethod
+	t.Order = typescriptify.DeclarationOrder(p.Order)
+	t.Prefix = p.Prefix
+	t.Suffix = p.Suffix
+	if len(p.Indent) > 0 {
+		t.Indent = p.Indent
+	}
+	if len(p.InterfacePrefix) > 0 {
+		t.InterfacePrefix = p.InterfacePrefix
+	}
+	t.ValidateTags = p.ValidateTags
+	t.DontExport = p.DontExport
+	t.CreateConstructor = !p.NoConstructor
+	t.Strict = p.Strict
+	t.BackupDir = p.BackupDir
+	t.BackupKeep = p.BackupKeep
+	for pkgPath, fileName := range p.PackageFiles {
+		t.WithPackageFile(pkgPath, fileName)
+	}
+	for _, managedType := range p.ManagedTypes {
+		t.ManageTypeName(managedType.Type, managedType.TypeOptions())
+	}
+
+	for _, modelsPackage := range p.Packages {
+		pkg := static.Package{Path: modelsPackage.Path, Names: modelsPackage.Structs, Enums: modelsPackage.Enums}
+		if p.AllOptional {
+			pkg.JSONOptions = []string{"omitempty"}
+		}
+		if err := static.AddPackage(t, pkg); err != nil {
+			return false, err
+		}
+	}
+	for _, customImport := range p.CustomImports {
+		t.AddImport(customImport)
+	}
+
+	switch {
+	case p.Diff:
+		report, err := t.Diff(p.Snapshot)
+		if p.UpdateSnapshot && errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, change := range report.Changes {
+			fmt.Println(change)
+		}
+		if len(p.Report) > 0 {
+			byts, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return false, err
+			}
+			if err := os.WriteFile(p.Report, byts, 0644); err != nil {
+				return false, err
+			}
+		}
+		if p.UpdateSnapshot {
+			if err := t.SaveSnapshot(p.Snapshot); err != nil {
+				return false, err
+			}
+		}
+		if report.Breaking {
+			fmt.Println("Breaking changes found")
+			return false, nil
+		}
+		logger.Info("No breaking changes", slog.String("snapshot", p.Snapshot))
+	case p.Check:
+		var err error
+		if len(p.TargetDir) > 0 {
+			err = t.CheckDir(p.TargetDir)
+		} else {
+			err = t.Check(p.TargetFile)
+		}
+		var drift *typescriptify.DriftError
+		if errors.As(err, &drift) {
+			fmt.Println(err.Error())
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		logger.Info("Up to date", slog.String("target", p.TargetDir+p.TargetFile))
+	case len(p.TargetDir) > 0:
+		if err := t.ConvertToDir(p.TargetDir); err != nil {
+			return false, err
+		}
+		logger.Info("Converted", slog.String("target", p.TargetDir))
+	default:
+		if err := t.ConvertToFile(p.TargetFile); err != nil {
+			return false, err
+		}
+		logger.Info("Converted", slog.String("target", p.TargetFile))
+	}
+	return true, nil
+}
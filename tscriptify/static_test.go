@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStaticOptions(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	ok, err := run(Params{
+		Static:           true,
+		TargetDir:        dir,
+		Order:            "insertion",
+		CamelCase:        true,
+		CamelCaseOptions: CamelCaseOptions{PreserveConsecutiveUppercase: true},
+		CreateFromMethod: true,
+		PackageFiles:     map[string]string{"github.com/GoodNotes/typescriptify-golang-structs/example/example-models": "people"},
+		Packages:         []ModelsPackage{{Path: "github.com/GoodNotes/typescriptify-golang-structs/example/example-models", Alias: "models", Structs: []string{"Address"}}},
+	})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	byts, err := os.ReadFile(filepath.Join(dir, "people.ts"))
+	assert.Nil(t, err)
+	code := string(byts)
+	assert.Contains(t, code, "static createFrom(source: any = {}) {")
+	assert.Contains(t, code, "    city: string;")
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fileState is used to find changed files.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// watch converts the targets, and converts them again (with their structs selected again) when the Go files
+// of their packages or one of the files change. Errors are reported, and the targets converted again after
+// the next change. If the targets were never loaded (i.e. a Go file doesn't compile), the packages and files
+// given for them (see targetSources) are watched.
+func watch(targets func() ([]Params, error), sources func() (patterns, files []string), files []string, interval time.Duration) {
+	var dirs []string // Kept after errors, to find the fix
+	for {
+		watched := files
+		params, err := targets()
+		if err == nil {
+			for _, p := range params {
+				tryRun(p)
+			}
+			var newDirs []string
+			if newDirs, err = packageDirs(params); err == nil {
+				dirs = newDirs
+			}
+		}
+		if err != nil {
+			logger.Error("Loading the targets failed", slog.String("error", err.Error()))
+			if dirs == nil {
+				patterns, sourceFiles := sources()
+				watched = append(append([]string{}, files...), sourceFiles...)
+				if sourceDirs, err := listDirs(patterns); err == nil {
+					dirs = sourceDirs
+				}
+			}
+		}
+
+		states := watchedFiles(dirs, watched)
+		logger.Info("Watching for changes", slog.Int("files", len(states)))
+		waitForChanges(states, func() map[string]fileState { return watchedFiles(dirs, watched) }, interval)
+		logger.Info("Changes found, converting again", slog.String("at", time.Now().Format("15:04:05")))
+	}
+}
+
+// tryRun converts one target, an error is reported instead of exiting.
+func tryRun(p Params) bool {
+	ok, err := run(p)
+	if err != nil {
+		logger.Error("Conversion failed", slog.String("error", err.Error()))
+		return false
+	}
+	return ok
+}
+
+// waitForChanges polls the files until they change, and then until they don't change during an interval
+// (many files are often saved at once).
+func waitForChanges(states map[string]fileState, current func() map[string]fileState, interval time.Duration) {
+	changed := false
+	for {
+		time.Sleep(interval)
+		newStates := current()
+		if !sameFiles(states, newStates) {
+			changed = true
+		} else if changed {
+			return
+		}
+		states = newStates
+	}
+}
+
+func sameFiles(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for fileName, state := range a {
+		if other, found := b[fileName]; !found || !other.modTime.Equal(state.modTime) || other.size != state.size {
+			return false
+		}
+	}
+	return true
+}
+
+// packageDirs returns the directories of the models packages.
+func packageDirs(params []Params) ([]string, error) {
+	var paths []string
+	for _, p := range params {
+		for _, pkg := range p.Packages {
+			paths = append(paths, pkg.Path)
+		}
+	}
+	return listDirs(paths)
+}
+
+// listDirs returns the directories of the packages matching `go list` patterns, packages which can't be found
+// are skipped.
+func listDirs(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	cmd := exec.Command("go", append([]string{"list", "-e", "-find", "-f", "{{.Dir}}"}, patterns...)...)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the models packages: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// targetSources returns the `go list` patterns of the packages (given as `path` or `alias=path`) and package
+// directories, and the Go files given as arguments (see selectStructs) of a target, without parsing them.
+// Paths are relative to dir (or the current directory if empty).
+func targetSources(paths, args []string, dir string) (patterns, files []string) {
+	for _, path := range paths {
+		if i := strings.Index(path, "="); i >= 0 {
+			path = path[i+1:]
+		}
+		if path = strings.TrimSpace(path); len(path) > 0 {
+			patterns = append(patterns, path)
+		}
+	}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		isFile := strings.HasSuffix(arg, ".go")
+		isDir := strings.HasPrefix(arg, ".") || strings.ContainsRune(arg, '/') || filepath.IsAbs(arg)
+		if !isFile && !isDir {
+			continue
+		}
+		if len(dir) > 0 && !filepath.IsAbs(arg) {
+			arg = filepath.Join(dir, arg)
+		}
+		if isFile {
+			fileNames, _ := filepath.Glob(arg)
+			files = append(files, fileNames...)
+		} else if abs, err := filepath.Abs(arg); err == nil {
+			patterns = append(patterns, abs) // `./models/...` becomes `/abs/models/...`
+		}
+	}
+	return patterns, files
+}
+
+// watchedFiles returns the state of the Go files in the directories, and of the files.
+func watchedFiles(dirs, files []string) map[string]fileState {
+	result := map[string]fileState{}
+	add := func(fileName string) {
+		if info, err := os.Stat(fileName); err == nil {
+			result[fileName] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+				add(filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	for _, fileName := range files {
+		add(fileName)
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameFiles(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	states := map[string]fileState{"a.go": {modTime: now, size: 10}, "b.go": {modTime: now, size: 20}}
+	for _, test := range []struct {
+		name     string
+		other    map[string]fileState
+		expected bool
+	}{
+		{name: "same", other: map[string]fileState{"a.go": {modTime: now, size: 10}, "b.go": {modTime: now, size: 20}}, expected: true},
+		{name: "same time in another location", other: map[string]fileState{"a.go": {modTime: now.UTC(), size: 10}, "b.go": {modTime: now, size: 20}}, expected: true},
+		{name: "modified", other: map[string]fileState{"a.go": {modTime: now.Add(time.Second), size: 10}, "b.go": {modTime: now, size: 20}}},
+		{name: "resized", other: map[string]fileState{"a.go": {modTime: now, size: 11}, "b.go": {modTime: now, size: 20}}},
+		{name: "removed", other: map[string]fileState{"a.go": {modTime: now, size: 10}}},
+		{name: "added", other: map[string]fileState{"a.go": {modTime: now, size: 10}, "b.go": {modTime: now, size: 20}, "c.go": {modTime: now}}},
+		{name: "renamed", other: map[string]fileState{"a.go": {modTime: now, size: 10}, "c.go": {modTime: now, size: 20}}},
+		{name: "empty", other: map[string]fileState{}},
+	} {
+		assert.Equal(t, test.expected, sameFiles(states, test.other), test.name)
+		assert.Equal(t, test.expected, sameFiles(test.other, states), test.name)
+	}
+	assert.True(t, sameFiles(nil, map[string]fileState{}))
+}
+
+func TestWatchedFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "models", "models.go"), "package models\n")
+	writeFile(t, filepath.Join(dir, "models", "models_test.go"), "package models\n")
+	writeFile(t, filepath.Join(dir, "models", "README.md"), "models\n")
+	writeFile(t, filepath.Join(dir, "models", "sub", "sub.go"), "package sub\n")
+	writeFile(t, filepath.Join(dir, "tscriptify.yaml"), "targets: []\n")
+
+	states := watchedFiles(
+		[]string{filepath.Join(dir, "models"), filepath.Join(dir, "missing")},
+		[]string{filepath.Join(dir, "tscriptify.yaml"), filepath.Join(dir, "missing.yaml")},
+	)
+	assert.Len(t, states, 3)
+	for _, fileName := range []string{filepath.Join("models", "models.go"), filepath.Join("models", "models_test.go"), "tscriptify.yaml"} {
+		assert.Contains(t, states, filepath.Join(dir, fileName))
+	}
+	assert.Equal(t, int64(len("package models\n")), states[filepath.Join(dir, "models", "models.go")].size)
+	assert.True(t, sameFiles(states, watchedFiles([]string{filepath.Join(dir, "models"), filepath.Join(dir, "missing")}, []string{filepath.Join(dir, "tscriptify.yaml"), filepath.Join(dir, "missing.yaml")})))
+
+	writeFile(t, filepath.Join(dir, "models", "models.go"), "package models\n\ntype Person struct{}\n")
+	assert.False(t, sameFiles(states, watchedFiles([]string{filepath.Join(dir, "models")}, []string{filepath.Join(dir, "tscriptify.yaml")})))
+}
+
+func TestTargetSources(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "models", "models.go"), "package models\n\ntype Person struct {\n")
+	writeFile(t, filepath.Join(dir, "models", "address.go"), "package models\n")
+
+	patterns, files := targetSources(
+		[]string{"example.com/models", "billing=example.com/billing", ""},
+		[]string{"Person", "billing.Invoice", "models/*.go", "./other/...", "missing/*.go"},
+		dir,
+	)
+	assert.Equal(t, []string{"example.com/models", "example.com/billing", filepath.Join(dir, "other", "...")}, patterns)
+	assert.Equal(t, []string{filepath.Join(dir, "models", "address.go"), filepath.Join(dir, "models", "models.go")}, files)
+}
+
+func TestRunUsageError(t *testing.T) {
+	t.Parallel()
+
+	// Usage errors are returned (not exiting), so that -watch goes on:
+	for _, p := range []Params{{}, {Diff: true}} {
+		_, err := run(p)
+		var usage usageError
+		assert.True(t, errors.As(err, &usage), "%v", err)
+	}
+}
+
+func TestRunStaticError(t *testing.T) {
+	dir := t.TempDir()
+	_, err := run(Params{
+		Static:     true,
+		TargetFile: filepath.Join(dir, "models.ts"),
+		Packages:   []ModelsPackage{{Path: "github.com/GoodNotes/typescriptify-golang-structs/example/example-models", Alias: "models", Structs: []string{"Missing"}}},
+	})
+	assert.NotNil(t, err)
+	_, statErr := os.Stat(filepath.Join(dir, "models.ts"))
+	assert.True(t, os.IsNotExist(statErr))
+}
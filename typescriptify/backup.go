@@ -0,0 +1,125 @@
+package typescriptify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	backupExtension  = ".backup"
+	backupTimeLayout = "2006-01-02T15_04_05.000000"
+)
+
+// Backup is a copy of a generated file, made before it was overwritten.
+type Backup struct {
+	FileName string    // Backup file
+	Time     time.Time // When the backup was made
+}
+
+// backup copies an existing file into BackupDir (with the same permissions), and removes the oldest backups
+// of the file if there are more than BackupKeep.
+func (t TypeScriptify) backup(fileName string) error {
+	info, err := os.Stat(fileName)
+	if os.IsNotExist(err) {
+		return nil // Nothing to back up
+	}
+	if err != nil {
+		return err
+	}
+	byts, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	prefix, err := backupPrefix(fileName)
+	if err != nil {
+		return err
+	}
+	backupFn := fmt.Sprintf("%s%s%s", prefix, time.Now().Format(backupTimeLayout), backupExtension)
+	if err := os.WriteFile(filepath.Join(t.BackupDir, backupFn), byts, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if t.BackupKeep <= 0 {
+		return nil
+	}
+	backups, err := t.Backups(fileName)
+	if err != nil {
+		return err
+	}
+	for i := t.BackupKeep; i < len(backups); i++ {
+		if err := os.Remove(backups[i].FileName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backups returns the backups of a file in BackupDir, newest first.
+func (t TypeScriptify) Backups(fileName string) ([]Backup, error) {
+	dir := t.BackupDir
+	if dir == "" {
+		dir = "."
+	}
+	prefix, err := backupPrefix(fileName)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, backupExtension) {
+			continue
+		}
+		// Fractional seconds are optional (and of any length) when parsing, so older backups are found too:
+		tm, err := time.ParseInLocation("2006-01-02T15_04_05", strings.TrimSuffix(name[len(prefix):], backupExtension), time.Local)
+		if err != nil {
+			continue // Backup of another file with the same prefix
+		}
+		result = append(result, Backup{FileName: filepath.Join(dir, name), Time: tm})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.After(result[j].Time)
+	})
+	return result, nil
+}
+
+// backupPrefix returns the start of the backup file names of a file: its name and a hash of its absolute
+// path, so that files with the same name in different directories can share BackupDir.
+func backupPrefix(fileName string) (string, error) {
+	abs, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(abs))
+	return filepath.Base(fileName) + "-" + hex.EncodeToString(hash[:4]) + "-", nil
+}
+
+// RestoreBackup replaces a file with one of its backups. The current file is backed up first (if BackupDir is
+// set), so a restore can be undone.
+func (t TypeScriptify) RestoreBackup(fileName string, backup Backup) error {
+	byts, err := os.ReadFile(backup.FileName)
+	if err != nil {
+		return err
+	}
+	if len(t.BackupDir) > 0 {
+		if err := t.backup(fileName); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(fileName, byts)
+}
@@ -0,0 +1,114 @@
+package typescriptify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupKeep(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, "backups")
+	assert.Nil(t, os.Mkdir(backupDir, 0755))
+	fileName := filepath.Join(dir, "models.ts")
+
+	converter := New().Add(Holliday{}).WithBackupDir(backupDir).WithBackupKeep(2)
+	for _, prefix := range []string{"A", "B", "C", "D"} {
+		_, err := converter.WithPrefix(prefix).UpdateFile(fileName)
+		assert.Nil(t, err)
+	}
+	// Unchanged, no backup:
+	_, err := converter.UpdateFile(fileName)
+	assert.Nil(t, err)
+
+	backups, err := converter.Backups(fileName)
+	assert.Nil(t, err)
+	if assert.Len(t, backups, 2) {
+		assert.True(t, backups[0].Time.After(backups[1].Time))
+		byts, err := os.ReadFile(backups[0].FileName)
+		assert.Nil(t, err)
+		assert.Contains(t, string(byts), "class CHolliday")
+	}
+}
+
+func TestBackupPermissions(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "models.ts")
+	assert.Nil(t, os.WriteFile(fileName, []byte("existing"), 0640))
+
+	converter := New().Add(Holliday{}).WithBackupDir(dir)
+	_, err := converter.UpdateFile(fileName)
+	assert.Nil(t, err)
+
+	backups, err := converter.Backups(fileName)
+	assert.Nil(t, err)
+	if assert.Len(t, backups, 1) {
+		info, err := os.Stat(backups[0].FileName)
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	}
+}
+
+func TestBackupSameFileName(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, "backups")
+	assert.Nil(t, os.Mkdir(backupDir, 0755))
+	web := filepath.Join(dir, "web", "models.ts")
+	mobile := filepath.Join(dir, "mobile", "models.ts")
+	for _, fileName := range []string{web, mobile} {
+		assert.Nil(t, os.Mkdir(filepath.Dir(fileName), 0755))
+		assert.Nil(t, os.WriteFile(fileName, []byte(fileName), 0644))
+	}
+
+	converter := New().Add(Holliday{}).WithBackupDir(backupDir).WithBackupKeep(1)
+	for _, fileName := range []string{web, mobile} {
+		_, err := converter.UpdateFile(fileName)
+		assert.Nil(t, err)
+	}
+
+	// Every file keeps its own backup:
+	for _, fileName := range []string{web, mobile} {
+		backups, err := converter.Backups(fileName)
+		assert.Nil(t, err)
+		if assert.Len(t, backups, 1) {
+			byts, err := os.ReadFile(backups[0].FileName)
+			assert.Nil(t, err)
+			assert.Equal(t, fileName, string(byts))
+		}
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "models.ts")
+	assert.Nil(t, os.WriteFile(fileName, []byte("existing"), 0644))
+	// Backups of other files are ignored:
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "models.ts-old-2020-01-01T10_00_00.backup"), nil, 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "other.ts-2020-01-01T10_00_00.backup"), nil, 0644))
+
+	converter := New().Add(Holliday{}).WithBackupDir(dir)
+	_, err := converter.UpdateFile(fileName)
+	assert.Nil(t, err)
+
+	backups, err := converter.Backups(fileName)
+	assert.Nil(t, err)
+	if !assert.Len(t, backups, 1) {
+		return
+	}
+	assert.Nil(t, converter.RestoreBackup(fileName, backups[0]))
+
+	byts, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "existing", string(byts))
+
+	// The generated file was backed up before restoring:
+	backups, err = converter.Backups(fileName)
+	assert.Nil(t, err)
+	assert.Len(t, backups, 2)
+}
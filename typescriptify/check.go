@@ -0,0 +1,77 @@
+package typescriptify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DriftError is returned by Check and CheckDir when generated files are outdated.
+type DriftError struct {
+	Files []string // Outdated, missing or removed files
+	Diff  string   // Unified diff from the existing to the generated files
+}
+
+func (e *DriftError) Error() string {
+	return fmt.Sprintf("generated code is outdated in %s:\n%s", strings.Join(e.Files, ", "), e.Diff)
+}
+
+// Check converts the models like ConvertToFile (preserving the custom code of the existing file) and
+// returns a *DriftError if the result differs from the existing file. Nothing is written, no backup is made.
+func (t TypeScriptify) Check(fileName string) error {
+	customCode, err := loadCustomCode(fileName)
+	if err != nil {
+		return err
+	}
+	converted, err := t.Convert(customCode)
+	if err != nil {
+		return err
+	}
+	return checkFiles([]generatedFile{{fileName: fileName, code: converted}})
+}
+
+// CheckDir converts the models like ConvertToDir and returns a *DriftError if the result differs from
+// the existing files. Nothing is written, no backup is made.
+func (t TypeScriptify) CheckDir(dir string) error {
+	files, err := t.convertDir(dir)
+	if err != nil {
+		return err
+	}
+	return checkFiles(files)
+}
+
+// checkFiles compares generated files with the existing ones.
+func checkFiles(files []generatedFile) error {
+	var drift DriftError
+	for _, file := range files {
+		existing, err := os.ReadFile(file.fileName)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		generated := fileHeader + file.code
+		if file.removed {
+			generated = "" // Generated files are never empty
+		}
+		if string(existing) == generated {
+			continue
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(existing)),
+			B:        difflib.SplitLines(generated),
+			FromFile: file.fileName,
+			ToFile:   file.fileName + " (generated)",
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		drift.Files = append(drift.Files, file.fileName)
+		drift.Diff += diff
+	}
+	if len(drift.Files) > 0 {
+		return &drift
+	}
+	return nil
+}
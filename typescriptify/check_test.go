@@ -0,0 +1,67 @@
+package typescriptify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	dir, backupDir := t.TempDir(), t.TempDir()
+	fileName := filepath.Join(dir, "models.ts")
+
+	converter := New().
+		Add(Holliday{}).
+		AddEnum(allWeekdaysV1).
+		WithBackupDir(backupDir)
+
+	var drift *DriftError
+	assert.True(t, errors.As(converter.Check(fileName), &drift))
+	assert.Equal(t, []string{fileName}, drift.Files)
+	_, err := os.Stat(fileName)
+	assert.True(t, os.IsNotExist(err))
+
+	customCode := "    //[Holliday:]\n    isWeekend(): boolean { return false; }\n\n    //[end]\n"
+	assert.Nil(t, os.WriteFile(fileName, []byte("export class Holliday {\n"+customCode+"}"), 0644))
+	assert.Nil(t, converter.ConvertToFile(fileName))
+	assert.Nil(t, converter.Check(fileName))
+
+	byts, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), customCode)
+
+	outdated := string(byts) + "\nexport class Removed {}"
+	assert.Nil(t, os.WriteFile(fileName, []byte(outdated), 0644))
+	err = converter.Check(fileName)
+	assert.True(t, errors.As(err, &drift))
+	assert.Contains(t, drift.Diff, "--- "+fileName+"\n")
+	assert.Contains(t, drift.Diff, "-export class Removed {}")
+
+	byts, err = os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, outdated, string(byts))
+	backups, err := os.ReadDir(backupDir)
+	assert.Nil(t, err)
+	assert.Len(t, backups, 1) // Only from ConvertToFile
+}
+
+func TestCheckDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	converter := New().
+		Add(Team{}).
+		WithInterface(true).
+		WithBackupDir("")
+	assert.Nil(t, converter.ConvertToDir(dir))
+	assert.Nil(t, converter.CheckDir(dir))
+
+	assert.Nil(t, os.Remove(filepath.Join(dir, "index.ts")))
+	var drift *DriftError
+	assert.True(t, errors.As(converter.CheckDir(dir), &drift))
+	assert.Equal(t, []string{filepath.Join(dir, "index.ts")}, drift.Files)
+}
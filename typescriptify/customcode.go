@@ -0,0 +1,86 @@
+package typescriptify
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+// Names of custom code regions (between `//[name:]` and `//[end]`) outside of declarations. Code in a region
+// named like a declaration is kept inside the class (or interface), or after the enum. Code in a region named
+// "@" + declaration name is kept after the declaration.
+const (
+	CustomCodeTop    = "@top"    // Top of the file, after the imports
+	CustomCodeBottom = "@bottom" // End of the file
+)
+
+// FooterEmitter is implemented by emitters adding code after the declarations of a file.
+type FooterEmitter interface {
+	Footer(model *ir.Model) (string, error)
+}
+
+// customCodeRegion returns the preserved custom code of a region outside of declarations, with its markers.
+func customCodeRegion(customCode map[string]string, name string) string {
+	code := customCode[name]
+	if len(code) == 0 {
+		return ""
+	}
+	return "//[" + name + ":]\n" + code + "\n//[end]"
+}
+
+// customCodeAfter returns the preserved custom code after a declaration, including the code named like
+// the declaration if it can't be kept inside (enums and typedefs).
+func customCodeAfter(customCode map[string]string, name string, inside bool) string {
+	var regions []string
+	if !inside {
+		regions = append(regions, customCodeRegion(customCode, name))
+	}
+	regions = append(regions, customCodeRegion(customCode, "@"+name))
+
+	result := ""
+	for _, region := range regions {
+		if region != "" {
+			result += "\n" + region
+		}
+	}
+	return result
+}
+
+// customCodeHeader returns the custom code at the top of the file.
+func customCodeHeader(customCode map[string]string) string {
+	if region := customCodeRegion(customCode, CustomCodeTop); region != "" {
+		return region + "\n"
+	}
+	return ""
+}
+
+// customCodeFooter returns the custom code at the end of the file, and the orphaned custom code of
+// declarations which are not converted anymore (with a warning), so that it isn't lost.
+func (t *TypeScriptify) customCodeFooter(model *ir.Model, customCode map[string]string) string {
+	var regions []string
+	for _, name := range orphanedCustomCode(model, customCode) {
+		t.logger().Warn("Declaration not converted anymore, its custom code is kept at the end of the file", slog.String("declaration", strings.TrimPrefix(name, "@")))
+		regions = append(regions, customCodeRegion(customCode, name))
+	}
+	if region := customCodeRegion(customCode, CustomCodeBottom); region != "" {
+		regions = append(regions, region)
+	}
+	return strings.Join(regions, "\n")
+}
+
+// orphanedCustomCode returns the (sorted) names of custom code regions of declarations not in the model.
+func orphanedCustomCode(model *ir.Model, customCode map[string]string) []string {
+	var result []string
+	for name, code := range customCode {
+		if name == CustomCodeTop || name == CustomCodeBottom || len(code) == 0 {
+			continue
+		}
+		if model.Declaration(strings.TrimPrefix(name, "@")) == nil {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
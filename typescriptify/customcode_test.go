@@ -0,0 +1,125 @@
+package typescriptify
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomCodeRegions(t *testing.T) {
+	t.Parallel()
+	fileName := filepath.Join(t.TempDir(), "models.ts")
+	existing := `//[@top:]
+const HOLIDAY_NAMES = ["Christmas"];
+//[end]
+//[Weekday:]
+export const WEEKEND = [Weekday.SATURDAY, Weekday.SUNDAY];
+//[end]
+export interface Holliday {
+	//[Holliday:]
+	extra?: string;
+	//[end]
+}
+//[@Holliday:]
+export function isKnown(h: Holliday): boolean { return HOLIDAY_NAMES.includes(h.name); }
+//[end]
+//[@bottom:]
+export default Holliday;
+//[end]`
+	assert.Nil(t, os.WriteFile(fileName, []byte(existing), 0644))
+
+	converter := New().
+		Add(Holliday{}).
+		AddEnum(allWeekdaysV1).
+		WithInterface(true).
+		WithIndent("\t").
+		WithBackupDir("")
+	assert.Nil(t, converter.ConvertToFile(fileName))
+	assertFileContent(t, fileName, `//[@top:]
+const HOLIDAY_NAMES = ["Christmas"];
+//[end]
+
+export enum Weekday {
+	SUNDAY = 0,
+	MONDAY = 1,
+	TUESDAY = 2,
+	WEDNESDAY = 3,
+	THURSDAY = 4,
+	FRIDAY = 5,
+	SATURDAY = 6,
+}
+//[Weekday:]
+export const WEEKEND = [Weekday.SATURDAY, Weekday.SUNDAY];
+//[end]
+export interface Holliday {
+	name: string;
+	weekday: Weekday;
+	//[Holliday:]
+	extra?: string;
+
+	//[end]
+}
+//[@Holliday:]
+export function isKnown(h: Holliday): boolean { return HOLIDAY_NAMES.includes(h.name); }
+//[end]
+//[@bottom:]
+export default Holliday;
+//[end]`)
+
+	// Regions are stable:
+	changed, err := converter.UpdateFile(fileName)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
+
+func TestOrphanedCustomCode(t *testing.T) {
+	t.Parallel()
+	fileName := filepath.Join(t.TempDir(), "models.ts")
+	existing := "export class Removed {\n\t//[Removed:]\n\tcustom(): void {}\n\t//[end]\n}\n"
+	assert.Nil(t, os.WriteFile(fileName, []byte(existing), 0644))
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		return attr
+	}}))
+	converter := New().Add(Holliday{}).WithInterface(true).WithBackupDir("").WithLogger(logger)
+	assert.Nil(t, converter.ConvertToFile(fileName))
+	assert.Equal(t, "level=WARN msg=\"Declaration not converted anymore, its custom code is kept at the end of the file\" declaration=Removed\n", logs.String())
+
+	byts, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), "//[Removed:]\n\tcustom(): void {}\n//[end]")
+
+	// Kept for the next conversion:
+	customCode, err := loadCustomCode(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"Removed": "\tcustom(): void {}"}, customCode)
+}
+
+func TestConvertToDirWithCustomCodeRegions(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "typescriptify.ts"), []byte("//[@top:]\nconst TEAM = 1;\n//[end]\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "example-models.ts"), []byte("//[@top:]\nconst MODELS = 1;\n//[end]\n"), 0644))
+
+	converter := New().Add(Team{}).WithBackupDir("")
+	assert.Nil(t, converter.ConvertToDir(dir))
+
+	byts, err := os.ReadFile(filepath.Join(dir, "typescriptify.ts"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), "const TEAM = 1;")
+	assert.NotContains(t, string(byts), "const MODELS = 1;")
+
+	byts, err = os.ReadFile(filepath.Join(dir, "example-models.ts"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), "const MODELS = 1;")
+	assert.NotContains(t, string(byts), "const TEAM = 1;")
+}
@@ -0,0 +1,111 @@
+package typescriptify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+// Severity of a diagnostic.
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning" // The conversion succeeds, but the result may not be the expected one
+	SeverityError   Severity = "error"
+)
+
+var (
+	// ErrUnnamedStruct is the warning for anonymous structs, converted as UnknownStruct (see AddTypeWithName).
+	ErrUnnamedStruct = errors.New("anonymous struct converted as UnknownStruct, use AddTypeWithName")
+	// ErrIgnoredField is the warning for channel and func fields, which are not converted (nor serialized to JSON).
+	ErrIgnoredField = errors.New("ignored field")
+	// ErrNameCollision is the error for different Go types converted with the same name.
+	ErrNameCollision = errors.New("name collision")
+	// ErrGenericStruct is the error for generic structs analyzed from source (see AddStaticStruct), their names
+	// would not include the type arguments.
+	ErrGenericStruct = errors.New("generic structs are not supported in static mode")
+)
+
+// Diagnostic is a problem found while analyzing the models. Type is the Go type of the struct added to the
+// converter, and Path the path of the field with the problem (empty for problems of the struct).
+type Diagnostic struct {
+	Severity Severity
+	FieldError
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Severity, d.FieldError.Error())
+}
+
+// Diagnostics are all the problems found while analyzing the models (see Diagnose), returned as an error by
+// Model (and the conversion) if there are errors. Errors of the fields can be found with errors.As and
+// *FieldError, or errors.Is (i.e. ErrUnsupportedType).
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	lines := []string{"conversion failed:"}
+	for _, diagnostic := range d {
+		lines = append(lines, diagnostic.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (d Diagnostics) Unwrap() []error {
+	var result []error
+	for i := range d {
+		result = append(result, &d[i].FieldError)
+	}
+	return result
+}
+
+// HasErrors returns true if there is at least one error.
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Diagnose analyzes the models (like Model) and returns all the problems found, warnings included. The error
+// is returned if the models can't be analyzed (i.e. invalid options, enums or declaration order), then the
+// diagnostics found before are returned with it.
+func (t *TypeScriptify) Diagnose() (Diagnostics, error) {
+	_, _, err := t.analyze()
+	return t.diagnostics, err
+}
+
+// report adds a diagnostic for a field of the struct being analyzed, warnings are errors in Strict mode.
+func (t *TypeScriptify) report(severity Severity, path string, err error) {
+	t.reportType(severity, t.rootType, path, err)
+}
+
+func (t *TypeScriptify) reportType(severity Severity, goType, path string, err error) {
+	if t.Strict {
+		severity = SeverityError
+	}
+	t.diagnostics = append(t.diagnostics, Diagnostic{Severity: severity, FieldError: FieldError{Type: goType, Path: path, Err: err}})
+}
+
+// checkNames reports different Go types with the same declaration name.
+func (t *TypeScriptify) checkNames(decls []*ir.Declaration) {
+	names := map[string]*ir.Declaration{}
+	for _, decl := range decls {
+		if other, found := names[decl.Name]; found && (other.GoType != decl.GoType || other.Package != decl.Package) {
+			t.reportType(SeverityError, decl.GoType, "", fmt.Errorf("%w: %s is also the name of %s", ErrNameCollision, decl.Name, other.GoType))
+			continue
+		}
+		names[decl.Name] = decl
+	}
+}
+
+// joinPath returns the path of a field of a struct at path.
+func joinPath(path, fieldName string) string {
+	if path == "" {
+		return fieldName
+	}
+	return path + "." + fieldName
+}
@@ -0,0 +1,82 @@
+package typescriptify
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type WithWarnings struct {
+	Name     string                 `json:"name"`
+	Options  struct{ Enabled bool } `json:"options"`
+	Callback func()                 `json:"-"`
+	Changes  chan string            `json:"changes"`
+	Amount   complex128             `json:"amount"`
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	converter := New().Add(WithWarnings{})
+	diagnostics, err := converter.Diagnose()
+	assert.Nil(t, err)
+	assert.True(t, diagnostics.HasErrors())
+	var messages []string
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, diagnostic.String())
+	}
+	assert.Equal(t, []string{
+		"warning: typescriptify.WithWarnings.Options: anonymous struct converted as UnknownStruct, use AddTypeWithName",
+		"warning: typescriptify.WithWarnings.Changes: ignored field: chan",
+		"error: typescriptify.WithWarnings.Amount: cannot find type for complex128 (amount/complex128)",
+	}, messages)
+
+	_, err = converter.Convert(nil)
+	assert.Equal(t, diagnostics, err)
+}
+
+func TestDiagnoseError(t *testing.T) {
+	t.Parallel()
+
+	diagnostics, err := New().Add(WithWarnings{}).WithOrder("unknown").Diagnose()
+	assert.NotNil(t, err)
+	assert.Len(t, diagnostics, 3)
+
+	_, err = New().AddEnum([]string{"a"}).Diagnose()
+	assert.NotNil(t, err)
+}
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	type Warnings struct {
+		Options struct{ Enabled bool } `json:"options"`
+		Changes chan string            `json:"changes"`
+	}
+	code, err := New().Add(Warnings{}).WithBackupDir("").Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, code, "options: UnknownStruct;")
+	assert.NotContains(t, code, "changes")
+
+	_, err = New().Add(Warnings{}).WithStrict(true).Convert(nil)
+	var diagnostics Diagnostics
+	if assert.True(t, errors.As(err, &diagnostics)) {
+		assert.Len(t, diagnostics, 2)
+		assert.True(t, errors.Is(err, ErrUnnamedStruct))
+		assert.True(t, errors.Is(err, ErrIgnoredField))
+		for _, diagnostic := range diagnostics {
+			assert.Equal(t, SeverityError, diagnostic.Severity)
+		}
+	}
+}
+
+func TestNameCollision(t *testing.T) {
+	t.Parallel()
+
+	anonymous := reflect.TypeOf(struct{ Name string }{})
+	_, err := New().Add(Dummy{}).AddTypeWithName(anonymous, "Dummy").Convert(nil)
+	assert.True(t, errors.Is(err, ErrNameCollision))
+	assert.Contains(t, err.Error(), "Dummy is also the name of typescriptify.Dummy")
+}
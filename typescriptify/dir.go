@@ -0,0 +1,278 @@
+package typescriptify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	defaultFileName = "types"
+	barrelFileName  = "index"
+)
+
+// ConvertToDir converts the models into one file per Go package (see PackageFiles and WithPackageFile),
+// with imports for declarations referenced from other files and an index file exporting everything.
+// Packages with the same name must be given different file names with WithPackageFile.
+//
+// Custom code (see ConvertToFile) is preserved in all the files. Generated files of an earlier conversion
+// without declarations anymore are removed (after a backup).
+func (t TypeScriptify) ConvertToDir(dir string) error {
+	_, err := t.UpdateDir(dir)
+	return err
+}
+
+// UpdateDir converts the models like ConvertToDir, but only files with changed content are written (see
+// UpdateFile). Returns true if any file was written.
+func (t TypeScriptify) UpdateDir(dir string) (bool, error) {
+	files, err := t.convertDir(dir)
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	changed := false
+	for _, file := range files {
+		if file.removed {
+			if err := t.removeFile(file.fileName); err != nil {
+				return changed, err
+			}
+			changed = true
+			continue
+		}
+		fileChanged, err := t.writeFile(file.fileName, file.code)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || fileChanged
+	}
+	return changed, nil
+}
+
+// generatedFile is a converted file, the code is without the header.
+type generatedFile struct {
+	fileName string
+	code     string
+	removed  bool // Generated by an earlier conversion, without declarations anymore
+}
+
+// removeFile removes a generated file, after a backup.
+func (t TypeScriptify) removeFile(fileName string) error {
+	if len(t.BackupDir) > 0 {
+		if err := t.backup(fileName); err != nil {
+			return err
+		}
+	}
+	return os.Remove(fileName)
+}
+
+// convertDir converts the models into files in dir (see ConvertToDir), without writing them.
+func (t *TypeScriptify) convertDir(dir string) ([]generatedFile, error) {
+	if t.DontExport {
+		return nil, fmt.Errorf("declarations must be exported to be imported from other files")
+	}
+	ext := t.fileExtension()
+
+	existingFiles, err := t.existingFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	fileCustomCode := map[string]map[string]string{}
+	for _, fileName := range existingFiles {
+		if fileCustomCode[fileName], err = loadCustomCode(fileName); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.checkOptions(); err != nil {
+		return nil, err
+	}
+	model, err := t.Model()
+	if err != nil {
+		return nil, err
+	}
+
+	// The custom code of declarations is kept if they are moved to another file, the code outside of
+	// declarations (and of declarations not converted anymore) stays in its file:
+	declarationCustomCode := map[string]string{}
+	for _, customCode := range fileCustomCode {
+		for name, code := range customCode {
+			if model.Declaration(strings.TrimPrefix(name, "@")) != nil {
+				declarationCustomCode[name] = code
+			}
+		}
+	}
+
+	files := map[string][]*ir.Declaration{}
+	declarationFiles := map[string]string{}
+	filePackages := map[string]string{}
+	for _, decl := range model.Declarations {
+		fileName := t.declarationFile(decl.Package)
+		if pkgPath, found := filePackages[fileName]; found && pkgPath != decl.Package && !t.packageFileSet(pkgPath, decl.Package) {
+			return nil, fmt.Errorf("packages %q and %q would be converted into the same file %s%s, set their file names with WithPackageFile", pkgPath, decl.Package, fileName, ext)
+		}
+		filePackages[fileName] = decl.Package
+		files[fileName] = append(files[fileName], decl)
+		declarationFiles[decl.Name] = fileName
+	}
+
+	fileNames := maps.Keys(files)
+	sort.Strings(fileNames)
+
+	var result []generatedFile
+	barrel := ""
+	for _, fileName := range fileNames {
+		customCode := maps.Clone(declarationCustomCode)
+		for name, code := range fileCustomCode[filepath.Join(dir, fileName+ext)] {
+			if _, found := customCode[name]; !found {
+				customCode[name] = code
+			}
+		}
+		emitter := t.emitter(customCode)
+
+		imports := append(typeImports(files[fileName]), t.fileImports(fileName, files[fileName], declarationFiles)...)
+		header, err := emitter.Header(model, imports)
+		if err != nil {
+			return nil, err
+		}
+		var parts []string
+		if header != "" {
+			parts = append(parts, header)
+		}
+		for _, decl := range files[fileName] {
+			declarationCode, err := emitter.Declaration(model, decl)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, declarationCode)
+		}
+		if footerEmitter, is := emitter.(FooterEmitter); is {
+			footer, err := footerEmitter.Footer(model)
+			if err != nil {
+				return nil, err
+			}
+			if footer != "" {
+				parts = append(parts, footer)
+			}
+		}
+		result = append(result, generatedFile{fileName: filepath.Join(dir, fileName+ext), code: strings.Join(parts, "\n")})
+		barrel += fmt.Sprintf("export * from %q;\n", t.fileModule(fileName))
+	}
+
+	barrelFile := filepath.Join(dir, barrelFileName+ext)
+	for _, fileName := range existingFiles {
+		if _, found := files[strings.TrimSuffix(filepath.Base(fileName), ext)]; found || fileName == barrelFile {
+			continue
+		}
+		generated, err := isGeneratedFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		if generated {
+			result = append(result, generatedFile{fileName: fileName, removed: true})
+		}
+	}
+	return append(result, generatedFile{fileName: barrelFile, code: barrel}), nil
+}
+
+// existingFiles returns the files in dir with the extension of the converted files (without declaration
+// files when converting to .ts files).
+func (t *TypeScriptify) existingFiles(dir string) ([]string, error) {
+	ext := t.fileExtension()
+	fileNames, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, fileName := range fileNames {
+		if ext == ".ts" && strings.HasSuffix(fileName, ".d.ts") {
+			continue
+		}
+		result = append(result, fileName)
+	}
+	return result, nil
+}
+
+// isGeneratedFile returns true if a file starts with the header of generated files.
+func isGeneratedFile(fileName string) (bool, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	header := make([]byte, len(fileHeader))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false, nil // Shorter than the header
+	}
+	return string(header) == fileHeader, nil
+}
+
+// packageFileSet returns true if the file names of both packages are set with WithPackageFile, so they are
+// converted into the same file on purpose.
+func (t *TypeScriptify) packageFileSet(pkgPaths ...string) bool {
+	for _, pkgPath := range pkgPaths {
+		if _, found := t.PackageFiles[pkgPath]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// declarationFile returns the file name (without extension) for declarations of types from a Go package.
+func (t *TypeScriptify) declarationFile(pkgPath string) string {
+	if fileName, found := t.PackageFiles[pkgPath]; found {
+		return fileName
+	}
+	if pkgPath == "" {
+		return defaultFileName
+	}
+	return path.Base(pkgPath)
+}
+
+// fileImports returns the imports of declarations from other files referenced in a file.
+func (t *TypeScriptify) fileImports(fileName string, decls []*ir.Declaration, declarationFiles map[string]string) []ir.Import {
+	imports := map[string]map[string]bool{}
+	for _, decl := range decls {
+		for _, ref := range decl.References() {
+			refFile, found := declarationFiles[ref]
+			if !found || refFile == fileName {
+				continue
+			}
+			module := t.fileModule(refFile)
+			if imports[module] == nil {
+				imports[module] = map[string]bool{}
+			}
+			imports[module][ref] = true
+		}
+	}
+	return sortedImports(imports, true)
+}
+
+// fileModule returns the module specifier of a converted file (without extension) in the same directory.
+// JavaScript ES modules can't be imported without their extension.
+func (t *TypeScriptify) fileModule(fileName string) string {
+	if t.JavaScript {
+		return "./" + fileName + t.fileExtension()
+	}
+	return "./" + fileName
+}
+
+func (t *TypeScriptify) fileExtension() string {
+	switch {
+	case t.JavaScript:
+		return ".js"
+	case t.Declaration:
+		return ".d.ts"
+	default:
+		return ".ts"
+	}
+}
@@ -0,0 +1,194 @@
+package typescriptify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	models "github.com/GoodNotes/typescriptify-golang-structs/example/example-models"
+	"github.com/stretchr/testify/assert"
+)
+
+type Team struct {
+	Name    string          `json:"name"`
+	Members []models.Person `json:"members"`
+	Office  *models.Address `json:"office"`
+}
+
+func TestConvertToDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	converter := New().
+		Add(Team{}).
+		WithPackageFile("github.com/GoodNotes/typescriptify-golang-structs/example/example-models", "people").
+		WithInterface(true).
+		WithIndent("\t").
+		WithBackupDir("")
+	assert.Nil(t, converter.ConvertToDir(dir))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "index.ts"), filepath.Join(dir, "people.ts"), filepath.Join(dir, "typescriptify.ts")}, files)
+
+	assertFileContent(t, filepath.Join(dir, "index.ts"), `export * from "./people";
+export * from "./typescriptify";
+`)
+	assertFileContent(t, filepath.Join(dir, "typescriptify.ts"), `import { Address, Person } from "./people";
+
+export interface Team {
+	name: string;
+	members: Person[];
+	office?: Address;
+}`)
+	assertFileContent(t, filepath.Join(dir, "people.ts"), `export interface Address {
+	city: string;
+	number: number;
+	country?: string;
+}
+export interface PersonalInfo {
+	hobby: string[];
+	pet_name: string;
+}
+export interface Person {
+	name: string;
+	personal_info: PersonalInfo;
+	nicknames: string[];
+	addresses: Address[];
+	address?: Address;
+	metadata: {[key:string]:string};
+	friends: Person[];
+}`)
+}
+
+func TestConvertToDirJavaScript(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	converter := New().
+		Add(Team{}).
+		WithPackageFile("github.com/GoodNotes/typescriptify-golang-structs/example/example-models", "people").
+		WithJavaScript(true).
+		WithIndent("\t").
+		WithBackupDir("")
+	assert.Nil(t, converter.ConvertToDir(dir))
+
+	assertFileContent(t, filepath.Join(dir, "index.js"), `export * from "./people.js";
+export * from "./typescriptify.js";
+`)
+	byts, err := os.ReadFile(filepath.Join(dir, "typescriptify.js"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), "import { Address, Person } from \"./people.js\";\n")
+}
+
+func TestConvertToDirTaggedStructs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	addressOptional := TagAll(reflect.TypeOf(models.Address{}), []string{"omitempty"})
+	converter := New().
+		AddTypeWithName(addressOptional, "Address").
+		WithInterface(true).
+		WithIndent("\t").
+		WithBackupDir("")
+	assert.Nil(t, converter.ConvertToDir(dir))
+
+	assertFileContent(t, filepath.Join(dir, "index.ts"), `export * from "./example-models";
+`)
+	assertFileContent(t, filepath.Join(dir, "example-models.ts"), `export interface Address {
+	city?: string;
+	number?: number;
+	country?: string;
+}`)
+}
+
+func TestConvertToDirWithCustomCode(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	customCode := "\t//[Team:]\n\tcustom(): string { return this.name; }\n\t//[end]\n"
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "typescriptify.ts"), []byte("export class Team {\n"+customCode+"}"), 0644))
+
+	converter := New().
+		Add(Team{}).
+		WithConstructor(false).
+		WithIndent("\t").
+		WithBackupDir("")
+	assert.Nil(t, converter.ConvertToDir(dir))
+
+	byts, err := os.ReadFile(filepath.Join(dir, "typescriptify.ts"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(byts), `import { Address, Person } from "./example-models";`)
+	assert.Contains(t, string(byts), "\tcustom(): string { return this.name; }")
+}
+
+func TestConvertToDirRemovedPackage(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	assert.Nil(t, New().Add(Team{}).WithBackupDir("").ConvertToDir(dir))
+	assert.FileExists(t, filepath.Join(dir, "example-models.ts"))
+	custom := filepath.Join(dir, "custom.ts")
+	assert.Nil(t, os.WriteFile(custom, []byte("export const custom = 1;\n"), 0644))
+
+	converter := New().Add(Team{}).WithPackageFile("github.com/GoodNotes/typescriptify-golang-structs/example/example-models", "people").WithBackupDir("")
+	assert.NotNil(t, converter.CheckDir(dir))
+	assert.Nil(t, converter.ConvertToDir(dir))
+	assert.NoFileExists(t, filepath.Join(dir, "example-models.ts"))
+	assert.FileExists(t, filepath.Join(dir, "people.ts"))
+	assert.FileExists(t, custom)
+	assert.Nil(t, converter.CheckDir(dir))
+	assertFileContent(t, filepath.Join(dir, "index.ts"), `export * from "./people";
+export * from "./typescriptify";
+`)
+}
+
+func TestConvertToDirSameFileName(t *testing.T) {
+	t.Parallel()
+
+	err := New().
+		Add(Team{}).
+		WithPackageFile("github.com/GoodNotes/typescriptify-golang-structs/example/example-models", "typescriptify").
+		ConvertToDir(t.TempDir())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "same file typescriptify.ts")
+
+	// Converted into the same file on purpose:
+	dir := t.TempDir()
+	assert.Nil(t, New().
+		Add(Team{}).
+		WithPackageFile("github.com/GoodNotes/typescriptify-golang-structs/example/example-models", "models").
+		WithPackageFile("github.com/GoodNotes/typescriptify-golang-structs/typescriptify", "models").
+		WithBackupDir("").
+		ConvertToDir(dir))
+	assertFileContent(t, filepath.Join(dir, "index.ts"), `export * from "./models";
+`)
+}
+
+func TestConvertToDirDeclarationFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	declarations := "declare module \"legacy\" {\n\t//[Legacy:]\n\tconst x: number;\n\t//[end]\n}\n"
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "legacy.d.ts"), []byte(fileHeader+declarations), 0644))
+
+	assert.Nil(t, New().Add(Team{}).WithBackupDir("").ConvertToDir(dir))
+	assertFileContent(t, filepath.Join(dir, "legacy.d.ts"), declarations)
+	byts, err := os.ReadFile(filepath.Join(dir, "typescriptify.ts"))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(byts), "const x: number;")
+}
+
+func TestConvertToDirNotExported(t *testing.T) {
+	t.Parallel()
+	converter := New().Add(Team{})
+	converter.DontExport = true
+	assert.NotNil(t, converter.ConvertToDir(t.TempDir()))
+}
+
+func assertFileContent(t *testing.T, fileName, expected string) {
+	byts, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, fileHeader+expected, string(byts))
+}
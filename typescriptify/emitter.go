@@ -0,0 +1,325 @@
+package typescriptify
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+	"golang.org/x/exp/maps"
+)
+
+// Emitter creates the code for the declarations of a model, see WithEmitter.
+type Emitter interface {
+	// Header returns the code before the declarations of a file. Imports are the types used in the file
+	// from other modules: managed types (see TypeOptions.ImportFrom) and declarations from other files.
+	Header(model *ir.Model, imports []ir.Import) (string, error)
+	// Declaration returns the code of one declaration.
+	Declaration(model *ir.Model, decl *ir.Declaration) (string, error)
+}
+
+// emitter returns the emitter set with WithEmitter, or the TypeScript (JavaScript or zod) emitter for the options.
+// Custom code is only preserved by the default emitters.
+func (t *TypeScriptify) emitter(customCode map[string]string) Emitter {
+	if t.Emitter != nil {
+		return t.Emitter
+	}
+	if t.JavaScript {
+		return &javaScriptEmitter{t: t, customCode: customCode}
+	}
+	if t.Zod {
+		return &zodEmitter{t: t, customCode: customCode}
+	}
+	return &typeScriptEmitter{t: t, customCode: customCode}
+}
+
+// typeScriptEmitter creates classes, interfaces (or both, see InterfaceAndClass) and enums, or their ambient
+// declarations.
+type typeScriptEmitter struct {
+	t          *TypeScriptify
+	customCode map[string]string
+}
+
+func (e *typeScriptEmitter) Header(model *ir.Model, imports []ir.Import) (string, error) {
+	result := e.t.customImportsCode()
+	for _, imp := range imports {
+		result += fmt.Sprintf("import { %s } from %q;\n", strings.Join(e.t.importedNames(model, imp), ", "), imp.Module)
+	}
+	if !e.t.Declaration {
+		result += customCodeHeader(e.customCode)
+	}
+	return result, nil
+}
+
+func (e *typeScriptEmitter) Declaration(model *ir.Model, decl *ir.Declaration) (string, error) {
+	result := ""
+	if decl.Kind == ir.KindEnum {
+		result = e.enum(decl)
+	} else {
+		result = e.class(decl)
+	}
+	if !e.t.Declaration {
+		result += customCodeAfter(e.customCode, decl.Name, decl.Kind != ir.KindEnum)
+	}
+	return result, nil
+}
+
+func (e *typeScriptEmitter) Footer(model *ir.Model) (string, error) {
+	if e.t.Declaration {
+		return "", nil
+	}
+	return e.t.customCodeFooter(model, e.customCode), nil
+}
+
+func (e *typeScriptEmitter) enum(decl *ir.Declaration) string {
+	t := e.t
+	result := "enum " + decl.Name + " {\n"
+	if t.Declaration {
+		result = "declare " + result
+	}
+	for _, member := range decl.Members {
+		result += fmt.Sprintf("%s%s = %s,\n", t.Indent, member.Name, enumMemberValue(member))
+	}
+	result += "}"
+
+	if !t.DontExport {
+		result = "export " + result
+	}
+	return docCommentPrefix(decl.Doc) + result
+}
+
+// class creates a class, or an interface if CreateInterface is set.
+func (e *typeScriptEmitter) class(decl *ir.Declaration) string {
+	t := e.t
+	entityName := decl.Name
+	result := ""
+	if t.CreateInterface {
+		result += fmt.Sprintf("interface %s {\n", entityName)
+	} else if t.Declaration {
+		result += fmt.Sprintf("declare class %s%s {\n", entityName, t.implementsClause(entityName))
+	} else {
+		result += fmt.Sprintf("class %s%s {\n", entityName, t.implementsClause(entityName))
+	}
+	if !t.DontExport {
+		result = "export " + result
+	}
+	result = docCommentPrefix(decl.Doc) + result
+
+	var fields []string
+	for _, fld := range decl.Fields {
+		fields = append(fields, e.field(fld)...)
+	}
+	if t.InterfaceAndClass {
+		result = t.classInterface(entityName, decl.Doc, fields) + "\n" + result
+	}
+	result += strings.Join(fields, "\n") + "\n"
+
+	createConstructor := t.CreateConstructor || t.CreateFromMethod
+	if !t.CreateInterface && t.Declaration {
+		sourceType := "any"
+		if t.InterfaceAndClass {
+			sourceType = t.InterfacePrefix + entityName + " | string"
+		}
+		if t.CreateFromMethod {
+			result += fmt.Sprintf("\n%sstatic createFrom(source?: %s): %s;\n", t.Indent, sourceType, entityName)
+		}
+		if createConstructor {
+			result += fmt.Sprintf("\n%sconstructor(source?: %s);\n", t.Indent, sourceType)
+		}
+	} else if !t.CreateInterface {
+		constructorBody, needsConvertValue := t.constructorBody(decl)
+		sourceParam := "source: any = {}"
+		parseSource := "if ('string' === typeof source) source = JSON.parse(source);"
+		if t.InterfaceAndClass {
+			interfaceName := t.InterfacePrefix + entityName
+			sourceParam = fmt.Sprintf("source: %s | string = {} as %s", interfaceName, interfaceName)
+		}
+		if t.CreateFromMethod {
+			result += fmt.Sprintf("\n%sstatic createFrom(%s) {\n", t.Indent, sourceParam)
+			result += fmt.Sprintf("%s%sreturn new %s(source);\n", t.Indent, t.Indent, entityName)
+			result += fmt.Sprintf("%s}\n", t.Indent)
+		}
+		if createConstructor {
+			if t.InterfaceAndClass {
+				// Converted values are assigned to fields typed with interfaces, so the source is used untyped:
+				sourceParam = strings.Replace(sourceParam, "source:", "input:", 1)
+				parseSource = "const source: any = 'string' === typeof input ? JSON.parse(input) : input;"
+			}
+			result += fmt.Sprintf("\n%sconstructor(%s) {\n", t.Indent, sourceParam)
+			result += t.Indent + t.Indent + parseSource + "\n"
+			result += constructorBody + "\n"
+			result += fmt.Sprintf("%s}\n", t.Indent)
+		}
+		if needsConvertValue && createConstructor {
+			result += "\n" + indentLines(strings.ReplaceAll(tsConvertValuesFunc, "\t", t.Indent), 1) + "\n"
+		}
+	}
+
+	if !t.Declaration {
+		result += t.customCodeBlock(e.customCode, entityName)
+	}
+
+	return result + "}"
+}
+
+func (e *typeScriptEmitter) field(fld *ir.Field) []string {
+	t := e.t
+	var lines []string
+	for _, line := range tsDocComment(fieldDoc(fld)) {
+		lines = append(lines, t.Indent+line)
+	}
+	ro := ""
+	if t.readOnly(fld) {
+		ro = "readonly "
+	}
+	name := fld.Name
+	if fld.Optional {
+		name += "?"
+	}
+	return append(lines, fmt.Sprint(t.Indent, ro, name, ": ", t.tsType(fld.Type), ";"))
+}
+
+// implementsClause returns the `implements` clause of a class if interfaces are created for classes.
+func (t *TypeScriptify) implementsClause(entityName string) string {
+	if !t.InterfaceAndClass {
+		return ""
+	}
+	return " implements " + t.InterfacePrefix + entityName
+}
+
+// classInterface creates the interface implemented by a class (see InterfaceAndClass).
+func (t *TypeScriptify) classInterface(entityName, doc string, fields []string) string {
+	result := fmt.Sprintf("interface %s {\n", t.InterfacePrefix+entityName)
+	if !t.DontExport {
+		result = "export " + result
+	}
+	result = docCommentPrefix(doc) + result
+	return result + strings.Join(fields, "\n") + "\n}"
+}
+
+// tsType returns the type of a field, struct references are typed with interfaces if InterfaceAndClass is set.
+func (t *TypeScriptify) tsType(typ *ir.TypeExpr) string {
+	return typ.Format(func(ref *ir.TypeExpr) string {
+		if ref.Kind == ir.TypeStruct && t.InterfaceAndClass {
+			return t.InterfacePrefix + ref.Name
+		}
+		return ref.Name
+	})
+}
+
+// readOnly returns true if a field is declared readonly (map fields never are).
+func (t *TypeScriptify) readOnly(fld *ir.Field) bool {
+	return t.ReadOnlyFields && fld.Type.Kind != ir.TypeMap
+}
+
+// constructorBody returns the constructor lines assigning the fields, and whether they use `convertValues`.
+func (t *TypeScriptify) constructorBody(decl *ir.Declaration) (string, bool) {
+	var lines []string
+	for _, fld := range decl.Fields {
+		lines = append(lines, fmt.Sprint(t.Indent, t.Indent, "this.", fld.Name, " = ", fieldInitializer(fld), ";"))
+	}
+	body := strings.Join(lines, "\n")
+	return body, strings.Contains(body, "this.convertValues")
+}
+
+// fieldInitializer returns the expression converting the JSON value of a field.
+func fieldInitializer(fld *ir.Field) string {
+	val := fmt.Sprintf(`source["%s"]`, fld.Name)
+	if fld.Transform != "" {
+		return strings.Replace(fld.Transform, "__VALUE__", val, -1)
+	}
+	if elem := fld.Type.Innermost(); elem.Kind == ir.TypeStruct {
+		return fmt.Sprintf("this.convertValues(%s, %s)", val, elem.Name)
+	}
+	if fld.Type.Kind == ir.TypeMap && fld.Type.Elem.Kind == ir.TypeStruct {
+		return fmt.Sprintf("this.convertValues(%s, %s, true)", val, fld.Type.Elem.Name)
+	}
+	return val
+}
+
+// fieldDoc returns the TSDoc of a field, with its constraints.
+func fieldDoc(fld *ir.Field) string {
+	if fld.Constraints == nil {
+		return fld.Doc
+	}
+	return strings.TrimSpace(fld.Doc + "\n" + constraintsDoc(fld.Constraints))
+}
+
+func enumMemberValue(member *ir.EnumMember) string {
+	if str, is := member.Value.(string); is {
+		return strconv.Quote(str)
+	}
+	return fmt.Sprint(member.Value)
+}
+
+// customCodeBlock returns the preserved custom code of a declaration, with its markers.
+func (t *TypeScriptify) customCodeBlock(customCode map[string]string, entityName string) string {
+	code := customCode[entityName]
+	if len(code) == 0 {
+		return ""
+	}
+	return t.Indent + "//[" + entityName + ":]\n" + code + "\n\n" + t.Indent + "//[end]\n"
+}
+
+// customImportsCode returns the imports added with AddImport.
+func (t *TypeScriptify) customImportsCode() string {
+	result := ""
+	// Put the custom imports, i.e.: `import Decimal from 'decimal.js'`
+	for _, cimport := range t.customImports {
+		result += cimport + "\n"
+	}
+	return result
+}
+
+// importedNames returns the names imported by an import, with the interfaces of imported classes if
+// InterfaceAndClass is set.
+func (t *TypeScriptify) importedNames(model *ir.Model, imp ir.Import) []string {
+	if !imp.Generated || !t.InterfaceAndClass {
+		return imp.Names
+	}
+	var names []string
+	for _, name := range imp.Names {
+		if decl := model.Declaration(name); decl != nil && decl.Kind == ir.KindStruct {
+			names = append(names, t.InterfacePrefix+name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// typeImports returns the imports of managed types used in declarations.
+func typeImports(decls []*ir.Declaration) []ir.Import {
+	imports := map[string]map[string]bool{}
+	for _, decl := range decls {
+		for _, fld := range decl.Fields {
+			fld.Type.Walk(func(typ *ir.TypeExpr) {
+				if typ.Kind != ir.TypeCustom || typ.ImportFrom == "" {
+					return
+				}
+				for _, name := range tsTypeIdentifiers(typ.Name) {
+					if imports[typ.ImportFrom] == nil {
+						imports[typ.ImportFrom] = map[string]bool{}
+					}
+					imports[typ.ImportFrom][name] = true
+				}
+			})
+		}
+	}
+	return sortedImports(imports, false)
+}
+
+// sortedImports returns one import per module, sorted by module and imported names.
+func sortedImports(imports map[string]map[string]bool, generated bool) []ir.Import {
+	modules := maps.Keys(imports)
+	sort.Strings(modules)
+
+	var result []ir.Import
+	for _, module := range modules {
+		names := maps.Keys(imports[module])
+		sort.Strings(names)
+		result = append(result, ir.Import{Module: module, Names: names, Generated: generated})
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package typescriptify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+	"github.com/stretchr/testify/assert"
+)
+
+// typeAliasEmitter creates type aliases instead of classes and enums.
+type typeAliasEmitter struct{}
+
+func (typeAliasEmitter) Header(model *ir.Model, imports []ir.Import) (string, error) {
+	return "// aliases", nil
+}
+
+func (typeAliasEmitter) Declaration(model *ir.Model, decl *ir.Declaration) (string, error) {
+	if decl.Kind == ir.KindEnum {
+		var values []string
+		for _, member := range decl.Members {
+			values = append(values, fmt.Sprint(member.Value))
+		}
+		return fmt.Sprintf("export type %s = %s;", decl.Name, strings.Join(values, " | ")), nil
+	}
+	var fields []string
+	for _, fld := range decl.Fields {
+		optional := ""
+		if fld.Optional {
+			optional = "?"
+		}
+		fields = append(fields, fmt.Sprintf("%s%s: %s", fld.Name, optional, fld.Type))
+	}
+	return fmt.Sprintf("export type %s = { %s };", decl.Name, strings.Join(fields, "; ")), nil
+}
+
+func TestCustomEmitter(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Holliday{}).
+		Add(WithMap{}).
+		AddEnum(allWeekdaysV1).
+		WithEmitter(typeAliasEmitter{}).
+		WithBackupDir("")
+
+	desiredResult := `// aliases
+export type Weekday = 0 | 1 | 2 | 3 | 4 | 5 | 6;
+export type Holliday = { name: string; weekday: Weekday };
+export type Address = { duration: number; text?: string; Text2?: string };
+export type WithMap = { simpleMap: {[key: string]: number}; mapObjects: {[key: string]: Address}; ptrMapObjects?: {[key: string]: Address} };`
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, desiredResult, typeScriptCode)
+}
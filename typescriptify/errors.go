@@ -0,0 +1,77 @@
+package typescriptify
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnsupportedType is the error (in a FieldError) of fields with types which can't be converted, like
+// complex numbers and unsafe pointers (channels and functions are ignored, see ErrIgnoredField).
+var ErrUnsupportedType = errors.New("cannot find type")
+
+// FieldError is an error converting a struct field (see Diagnostics).
+type FieldError struct {
+	Type string // Go type of the struct, i.e. `models.Order`
+	Path string // Path of the field in the struct, i.e. `Items[].Price` for a field of the structs in Items
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %s", e.Type, e.Err)
+	}
+	return fmt.Sprintf("%s.%s: %s", e.Type, e.Path, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// fieldElemPath returns the path from a field to the struct in its (nested) slices and maps, i.e. `[]` for a
+// slice.
+func fieldElemPath(typ GoType) string {
+	path := ""
+	for {
+		switch typ.Kind() {
+		case reflect.Ptr:
+		case reflect.Slice, reflect.Array:
+			path += "[]"
+		case reflect.Map:
+			path += "{}"
+		default:
+			return path
+		}
+		typ = typ.Elem()
+	}
+}
+
+// EnumError is an error adding enum values, see TryAddEnum.
+type EnumError struct {
+	Type string // Go type of the values, i.e. `[]models.Weekday`
+	Err  error
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("enum values %s: %s", e.Type, e.Err)
+}
+
+func (e *EnumError) Unwrap() error {
+	return e.Err
+}
+
+// TagError is an error setting struct tags, see TryAddFieldTags and TryTagAll.
+type TagError struct {
+	Type  string // Go type of the struct
+	Field string
+	Tag   string
+	Err   error
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("%s.%s: invalid tag %q: %s", e.Type, e.Field, e.Tag, e.Err)
+}
+
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
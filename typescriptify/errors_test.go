@@ -0,0 +1,81 @@
+package typescriptify
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type OrderItem struct {
+	Name    string      `json:"name"`
+	Amount  complex128  `json:"amount"`
+	Updates chan string `json:"updates"`
+}
+
+type Order struct {
+	ID    string       `json:"id"`
+	Items []*OrderItem `json:"items"`
+	Total complex64    `json:"total"`
+}
+
+func TestFieldErrorPath(t *testing.T) {
+	t.Parallel()
+
+	_, err := New().Add(Order{}).Convert(nil)
+	var fieldErr *FieldError
+	if assert.True(t, errors.As(err, &fieldErr)) {
+		assert.Equal(t, "typescriptify.Order", fieldErr.Type)
+		assert.Equal(t, "Items[].Amount", fieldErr.Path)
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+	}
+	assert.Equal(t, `conversion failed:
+error: typescriptify.Order.Items[].Amount: cannot find type for complex128 (amount/complex128)
+warning: typescriptify.Order.Items[].Updates: ignored field: chan
+error: typescriptify.Order.Total: cannot find type for complex64 (total/complex64)`, err.Error())
+}
+
+func TestEnumErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, values := range map[string]interface{}{
+		"not a slice":       Weekday(1),
+		"no values":         []Weekday{},
+		"missing Value":     []struct{ TSName string }{{TSName: "A"}},
+		"missing TSName":    []struct{ Value int }{{Value: 1}},
+		"TSName not string": []struct{ Value, TSName int }{{Value: 1, TSName: 2}},
+		"no TSName method":  []int{1, 2},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := New().TryAddEnum(values)
+			var enumErr *EnumError
+			assert.True(t, errors.As(err, &enumErr), "%v", err)
+		})
+	}
+
+	// AddEnum returns the error when converting:
+	_, err := New().AddEnum([]int{1}).Add(Dummy{}).Convert(nil)
+	var enumErr *EnumError
+	if assert.True(t, errors.As(err, &enumErr)) {
+		assert.Equal(t, "[]int", enumErr.Type)
+	}
+}
+
+func TestTagErrors(t *testing.T) {
+	t.Parallel()
+
+	invalid := reflect.StructOf([]reflect.StructField{
+		{Name: "A", Type: reflect.TypeOf(""), Tag: `json:"a"`},
+		{Name: "B", Type: reflect.TypeOf(""), Tag: `json:b`},
+		{Name: "C", Type: reflect.TypeOf("")},
+	})
+	typ, err := TryTagAll(invalid, []string{"omitempty"})
+	var tagErr *TagError
+	if assert.True(t, errors.As(err, &tagErr)) {
+		assert.Equal(t, "B", tagErr.Field)
+	}
+	assert.Equal(t, `json:"a,omitempty"`, string(typ.Field(0).Tag))
+	assert.Equal(t, `json:b`, string(typ.Field(1).Tag))
+	assert.Equal(t, ``, string(typ.Field(2).Tag))
+}
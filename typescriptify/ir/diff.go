@@ -0,0 +1,163 @@
+package ir
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind is the kind of a change between two models.
+type ChangeKind string
+
+const (
+	DeclarationAdded   ChangeKind = "declaration-added"
+	DeclarationRemoved ChangeKind = "declaration-removed"
+	DeclarationKind    ChangeKind = "declaration-kind-changed"
+	FieldAdded         ChangeKind = "field-added"
+	FieldRemoved       ChangeKind = "field-removed"
+	FieldType          ChangeKind = "field-type-changed"
+	FieldRequired      ChangeKind = "field-required"
+	FieldOptional      ChangeKind = "field-optional"
+	EnumMemberAdded    ChangeKind = "enum-member-added"
+	EnumMemberRemoved  ChangeKind = "enum-member-removed"
+	EnumMemberValue    ChangeKind = "enum-member-value-changed"
+)
+
+// breakingChanges are the changes which can break clients of the previous model.
+var breakingChanges = map[ChangeKind]bool{
+	DeclarationRemoved: true,
+	DeclarationKind:    true,
+	FieldRemoved:       true,
+	FieldType:          true,
+	FieldRequired:      true,
+	EnumMemberRemoved:  true,
+	EnumMemberValue:    true,
+}
+
+// Change is a difference between two models.
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Breaking bool       `json:"breaking"`
+	Path     string     `json:"path"` // Declaration, `Declaration.field` or `Enum.MEMBER`
+	Old      string     `json:"old,omitempty"`
+	New      string     `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	severity := "non-breaking"
+	if c.Breaking {
+		severity = "BREAKING"
+	}
+	result := fmt.Sprintf("%s: %s %s", severity, c.Kind, c.Path)
+	if c.Old != "" || c.New != "" {
+		result += fmt.Sprintf(" (%s -> %s)", c.Old, c.New)
+	}
+	return result
+}
+
+// Report lists the changes between two models, breaking changes first.
+type Report struct {
+	Breaking bool     `json:"breaking"` // There is at least one breaking change
+	Changes  []Change `json:"changes"`
+}
+
+// Compare returns the changes from the old to the new model. Declarations are matched by name, fields by
+// (JSON) name and enum members by name. Docs and constraints are ignored.
+func Compare(old, new *Model) Report {
+	var changes []Change
+	add := func(kind ChangeKind, path, oldValue, newValue string) {
+		changes = append(changes, Change{Kind: kind, Breaking: breakingChanges[kind], Path: path, Old: oldValue, New: newValue})
+	}
+
+	for _, oldDecl := range old.Declarations {
+		newDecl := new.Declaration(oldDecl.Name)
+		switch {
+		case newDecl == nil:
+			add(DeclarationRemoved, oldDecl.Name, "", "")
+		case newDecl.Kind != oldDecl.Kind:
+			add(DeclarationKind, oldDecl.Name, string(oldDecl.Kind), string(newDecl.Kind))
+		case newDecl.Kind == KindEnum:
+			compareMembers(oldDecl, newDecl, add)
+		default:
+			compareFields(oldDecl, newDecl, add)
+		}
+	}
+	for _, newDecl := range new.Declarations {
+		if old.Declaration(newDecl.Name) == nil {
+			add(DeclarationAdded, newDecl.Name, "", "")
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Breaking && !changes[j].Breaking
+	})
+	report := Report{Changes: changes}
+	report.Breaking = len(changes) > 0 && changes[0].Breaking
+	return report
+}
+
+func compareFields(oldDecl, newDecl *Declaration, add func(kind ChangeKind, path, oldValue, newValue string)) {
+	newFields := map[string]*Field{}
+	for _, fld := range newDecl.Fields {
+		newFields[fld.Name] = fld
+	}
+	oldFields := map[string]bool{}
+	for _, oldFld := range oldDecl.Fields {
+		oldFields[oldFld.Name] = true
+		path := oldDecl.Name + "." + oldFld.Name
+		newFld, found := newFields[oldFld.Name]
+		if !found {
+			add(FieldRemoved, path, oldFld.Type.String(), "")
+			continue
+		}
+		if oldType, newType := oldFld.Type.String(), newFld.Type.String(); oldType != newType {
+			add(FieldType, path, oldType, newType)
+		}
+		if oldFld.Optional && !newFld.Optional {
+			add(FieldRequired, path, "", "")
+		} else if !oldFld.Optional && newFld.Optional {
+			add(FieldOptional, path, "", "")
+		}
+	}
+	for _, newFld := range newDecl.Fields {
+		if !oldFields[newFld.Name] {
+			add(FieldAdded, newDecl.Name+"."+newFld.Name, "", newFld.Type.String())
+		}
+	}
+}
+
+func compareMembers(oldDecl, newDecl *Declaration, add func(kind ChangeKind, path, oldValue, newValue string)) {
+	newMembers := map[string]*EnumMember{}
+	for _, member := range newDecl.Members {
+		newMembers[member.Name] = member
+	}
+	oldMembers := map[string]bool{}
+	for _, oldMember := range oldDecl.Members {
+		oldMembers[oldMember.Name] = true
+		path := oldDecl.Name + "." + oldMember.Name
+		newMember, found := newMembers[oldMember.Name]
+		if !found {
+			add(EnumMemberRemoved, path, memberValue(oldMember), "")
+			continue
+		}
+		if oldValue, newValue := memberValue(oldMember), memberValue(newMember); oldValue != newValue {
+			add(EnumMemberValue, path, oldValue, newValue)
+		}
+	}
+	for _, newMember := range newDecl.Members {
+		if !oldMembers[newMember.Name] {
+			add(EnumMemberAdded, newDecl.Name+"."+newMember.Name, "", memberValue(newMember))
+		}
+	}
+}
+
+// memberValue returns an enum value as in code, numbers read from JSON (float64) are formatted like integers.
+func memberValue(member *EnumMember) string {
+	switch value := member.Value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return fmt.Sprint(member.Value)
+}
@@ -0,0 +1,51 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	old := &Model{Declarations: []*Declaration{
+		{Name: "Weekday", Kind: KindEnum, Members: []*EnumMember{{Name: "SUNDAY", Value: float64(0)}, {Name: "MONDAY", Value: float64(1)}}},
+		{Name: "Person", Kind: KindStruct, Fields: []*Field{
+			{Name: "name", Type: Primitive("string")},
+			{Name: "age", Type: Primitive("number"), Optional: true},
+			{Name: "nickname", Type: Primitive("string")},
+			{Name: "addresses", Type: Array(Struct("Address"))},
+		}},
+		{Name: "Address", Kind: KindStruct},
+		{Name: "Removed", Kind: KindStruct},
+	}}
+	new := &Model{Declarations: []*Declaration{
+		{Name: "Weekday", Kind: KindEnum, Members: []*EnumMember{{Name: "MONDAY", Value: int64(1)}, {Name: "TUESDAY", Value: int64(2)}}},
+		{Name: "Person", Kind: KindStruct, Fields: []*Field{
+			{Name: "name", Type: Primitive("string"), Optional: true},
+			{Name: "age", Type: Primitive("number")},
+			{Name: "addresses", Type: Map(Primitive("string"), Struct("Address"))},
+			{Name: "email", Type: Primitive("string")},
+		}},
+		{Name: "Address", Kind: KindStruct},
+		{Name: "Added", Kind: KindStruct},
+	}}
+
+	report := Compare(old, new)
+	assert.True(t, report.Breaking)
+	assert.Equal(t, []Change{
+		{Kind: EnumMemberRemoved, Breaking: true, Path: "Weekday.SUNDAY", Old: "0"},
+		{Kind: FieldRequired, Breaking: true, Path: "Person.age"},
+		{Kind: FieldRemoved, Breaking: true, Path: "Person.nickname", Old: "string"},
+		{Kind: FieldType, Breaking: true, Path: "Person.addresses", Old: "Address[]", New: "{[key: string]: Address}"},
+		{Kind: DeclarationRemoved, Breaking: true, Path: "Removed"},
+		{Kind: EnumMemberAdded, Path: "Weekday.TUESDAY", New: "2"},
+		{Kind: FieldOptional, Path: "Person.name"},
+		{Kind: FieldAdded, Path: "Person.email", New: "string"},
+		{Kind: DeclarationAdded, Path: "Added"},
+	}, report.Changes)
+	assert.Equal(t, "BREAKING: field-type-changed Person.addresses (Address[] -> {[key: string]: Address})", report.Changes[3].String())
+
+	assert.Equal(t, Report{}, Compare(new, new))
+}
@@ -0,0 +1,189 @@
+// Package ir is the intermediate representation of converted Go types.
+//
+// The analysis of Go types (see typescriptify.TypeScriptify.Model) creates a Model with the declarations
+// (structs and enums), their fields and TypeScript type expressions. Emitters create the code from the model.
+package ir
+
+import (
+	"fmt"
+)
+
+// Kind is the kind of a declaration.
+type Kind string
+
+const (
+	KindStruct Kind = "struct"
+	KindEnum   Kind = "enum"
+)
+
+// TypeKind is the kind of a type expression.
+type TypeKind string
+
+const (
+	TypePrimitive TypeKind = "primitive" // `string`, `number`, `boolean` or `any`
+	TypeStruct    TypeKind = "struct"    // Reference to a struct declaration
+	TypeEnum      TypeKind = "enum"      // Reference to an enum declaration
+	TypeArray     TypeKind = "array"     // Array of Elem
+	TypeMap       TypeKind = "map"       // Object with Key keys and Elem values
+	TypeCustom    TypeKind = "custom"    // TypeScript type set with `ts_type` or TypeOptions
+)
+
+// Model contains all the converted declarations.
+type Model struct {
+	Declarations []*Declaration `json:"declarations"`
+}
+
+// Declaration returns the declaration with the given (TypeScript) name, or nil if there is none.
+func (m *Model) Declaration(name string) *Declaration {
+	for _, decl := range m.Declarations {
+		if decl.Name == name {
+			return decl
+		}
+	}
+	return nil
+}
+
+// Declaration is a converted struct or enum.
+type Declaration struct {
+	Name    string        `json:"name"`              // TypeScript name, with prefix and suffix
+	GoType  string        `json:"goType"`            // Go type, i.e. `models.Person`
+	Package string        `json:"package,omitempty"` // Go package path, empty for anonymous structs
+	Kind    Kind          `json:"kind"`
+	Doc     string        `json:"doc,omitempty"`
+	Fields  []*Field      `json:"fields,omitempty"`  // Struct fields
+	Members []*EnumMember `json:"members,omitempty"` // Enum members
+}
+
+// References returns the names of the declarations used in the fields, without duplicates.
+func (d *Declaration) References() []string {
+	var result []string
+	found := map[string]bool{}
+	for _, fld := range d.Fields {
+		fld.Type.Walk(func(typ *TypeExpr) {
+			if (typ.Kind == TypeStruct || typ.Kind == TypeEnum) && !found[typ.Name] {
+				found[typ.Name] = true
+				result = append(result, typ.Name)
+			}
+		})
+	}
+	return result
+}
+
+// Field is a struct field, as serialized to JSON.
+type Field struct {
+	Name        string       `json:"name"` // JSON name
+	GoName      string       `json:"goName"`
+	Type        *TypeExpr    `json:"type"`
+	Optional    bool         `json:"optional,omitempty"`
+	Doc         string       `json:"doc,omitempty"`
+	Transform   string       `json:"transform,omitempty"` // Expression converting the JSON value `__VALUE__`, see `ts_transform`
+	Constraints *Constraints `json:"constraints,omitempty"`
+}
+
+// TypeExpr is a TypeScript type.
+type TypeExpr struct {
+	Kind       TypeKind  `json:"kind"`
+	Name       string    `json:"name,omitempty"`       // Primitive type, declaration name or custom TypeScript type
+	Key        *TypeExpr `json:"key,omitempty"`        // Map keys
+	Elem       *TypeExpr `json:"elem,omitempty"`       // Array elements and map values
+	ImportFrom string    `json:"importFrom,omitempty"` // Module of a custom type
+}
+
+func Primitive(name string) *TypeExpr {
+	return &TypeExpr{Kind: TypePrimitive, Name: name}
+}
+
+func Struct(name string) *TypeExpr {
+	return &TypeExpr{Kind: TypeStruct, Name: name}
+}
+
+func Enum(name string) *TypeExpr {
+	return &TypeExpr{Kind: TypeEnum, Name: name}
+}
+
+func Array(elem *TypeExpr) *TypeExpr {
+	return &TypeExpr{Kind: TypeArray, Elem: elem}
+}
+
+func Map(key, elem *TypeExpr) *TypeExpr {
+	return &TypeExpr{Kind: TypeMap, Key: key, Elem: elem}
+}
+
+func Custom(tsType, importFrom string) *TypeExpr {
+	return &TypeExpr{Kind: TypeCustom, Name: tsType, ImportFrom: importFrom}
+}
+
+// Walk calls fn for the type and all the types it contains.
+func (e *TypeExpr) Walk(fn func(*TypeExpr)) {
+	if e == nil {
+		return
+	}
+	fn(e)
+	e.Key.Walk(fn)
+	e.Elem.Walk(fn)
+}
+
+// Innermost returns the element type of (nested) arrays, or the type itself.
+func (e *TypeExpr) Innermost() *TypeExpr {
+	for e.Kind == TypeArray {
+		e = e.Elem
+	}
+	return e
+}
+
+// String returns the type in TypeScript.
+func (e *TypeExpr) String() string {
+	return e.Format(nil)
+}
+
+// Format returns the type in TypeScript, with declaration references named by name (if not nil).
+func (e *TypeExpr) Format(name func(ref *TypeExpr) string) string {
+	switch e.Kind {
+	case TypeArray:
+		return e.Elem.Format(name) + "[]"
+	case TypeMap:
+		return fmt.Sprintf("{[key: %s]: %s}", e.Key.Format(name), e.Elem.Format(name))
+	case TypeStruct, TypeEnum:
+		if name != nil {
+			return name(e)
+		}
+	}
+	return e.Name
+}
+
+// EnumMember is a named enum value.
+type EnumMember struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"` // String or number
+}
+
+// Import is a list of types imported from a module.
+type Import struct {
+	Module    string   `json:"module"`
+	Names     []string `json:"names"`
+	Generated bool     `json:"generated,omitempty"` // Names are declarations of the model (from another generated file)
+}
+
+// Constraints are validation rules of a field. Lengths and numbers of items are inclusive, numbers are
+// kept as written in the source.
+type Constraints struct {
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	MinItems         *int     `json:"minItems,omitempty"`
+	MaxItems         *int     `json:"maxItems,omitempty"`
+	Minimum          string   `json:"minimum,omitempty"`
+	Maximum          string   `json:"maximum,omitempty"`
+	ExclusiveMinimum string   `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum string   `json:"exclusiveMaximum,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	Format           string   `json:"format,omitempty"`
+	OneOf            []string `json:"oneOf,omitempty"`
+	OneOfStrings     bool     `json:"oneOfStrings,omitempty"` // OneOf values are strings (not numbers)
+}
+
+// IsZero returns true if there are no constraints.
+func (c Constraints) IsZero() bool {
+	return c.MinLength == nil && c.MaxLength == nil && c.MinItems == nil && c.MaxItems == nil &&
+		c.Minimum == "" && c.Maximum == "" && c.ExclusiveMinimum == "" && c.ExclusiveMaximum == "" &&
+		c.Pattern == "" && c.Format == "" && len(c.OneOf) == 0
+}
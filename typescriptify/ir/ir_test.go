@@ -0,0 +1,29 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeExprFormat(t *testing.T) {
+	t.Parallel()
+
+	typ := Map(Primitive("string"), Array(Array(Struct("Address"))))
+	assert.Equal(t, "{[key: string]: Address[][]}", typ.String())
+	assert.Equal(t, "{[key: string]: IAddress[][]}", typ.Format(func(ref *TypeExpr) string { return "I" + ref.Name }))
+	assert.Equal(t, Struct("Address"), typ.Elem.Innermost())
+	assert.Equal(t, "Money | null", Custom("Money | null", "@acme/money").String())
+}
+
+func TestDeclarationReferences(t *testing.T) {
+	t.Parallel()
+
+	decl := &Declaration{Name: "Person", Kind: KindStruct, Fields: []*Field{
+		{Name: "address", Type: Struct("Address")},
+		{Name: "weekdays", Type: Array(Enum("Weekday"))},
+		{Name: "addresses", Type: Map(Primitive("string"), Struct("Address"))},
+		{Name: "name", Type: Primitive("string")},
+	}}
+	assert.Equal(t, []string{"Address", "Weekday"}, decl.References())
+}
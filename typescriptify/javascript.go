@@ -0,0 +1,181 @@
+package typescriptify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+const jsConvertValuesFunc = `/**
+ * @param {any} a
+ * @param {any} classs
+ * @param {boolean} [asMap]
+ * @returns {any}
+ */
+convertValues(a, classs, asMap = false) {
+	if (!a) {
+		return a;
+	}
+	if (a.slice) {
+		return a.map(elem => this.convertValues(elem, classs));
+	} else if ("object" === typeof a) {
+		if (asMap) {
+			for (const key of Object.keys(a)) {
+				a[key] = new classs(a[key]);
+			}
+			return a;
+		}
+		return new classs(a);
+	}
+	return a;
+}`
+
+// javaScriptEmitter creates classes (or JSDoc typedefs if CreateInterface is set) and frozen enum objects,
+// typed with JSDoc.
+type javaScriptEmitter struct {
+	t          *TypeScriptify
+	customCode map[string]string
+}
+
+func (e *javaScriptEmitter) Header(model *ir.Model, imports []ir.Import) (string, error) {
+	result := "// @ts-check\n" + e.t.customImportsCode()
+	for _, imp := range imports {
+		names := e.t.importedNames(model, imp)
+		// Managed types and typedefs are only used in JSDoc, they must be imported with JSDoc:
+		if !imp.Generated || e.t.CreateInterface {
+			for _, name := range names {
+				result += fmt.Sprintf("/** @typedef {import(%q).%s} %s */\n", imp.Module, name, name)
+			}
+		} else {
+			result += fmt.Sprintf("import { %s } from %q;\n", strings.Join(names, ", "), imp.Module)
+		}
+	}
+	return result + customCodeHeader(e.customCode), nil
+}
+
+func (e *javaScriptEmitter) Footer(model *ir.Model) (string, error) {
+	return e.t.customCodeFooter(model, e.customCode), nil
+}
+
+func (e *javaScriptEmitter) Declaration(model *ir.Model, decl *ir.Declaration) (string, error) {
+	if decl.Kind == ir.KindEnum {
+		return e.t.jsEnum(decl) + customCodeAfter(e.customCode, decl.Name, false), nil
+	}
+
+	t := e.t
+	var fields, properties []string
+	for _, fld := range decl.Fields {
+		lines, property := t.jsField(fld)
+		fields = append(fields, lines...)
+		properties = append(properties, property)
+	}
+	if t.CreateInterface {
+		return jsTypedefComment(decl.Name, decl.Doc, properties) + customCodeAfter(e.customCode, decl.Name, false), nil
+	}
+
+	entityName := decl.Name
+	result := fmt.Sprintf("class %s {\n", entityName)
+	if !t.DontExport {
+		result = "export " + result
+	}
+	result = docCommentPrefix(decl.Doc) + result
+	result += strings.Join(fields, "\n") + "\n"
+
+	constructorBody, needsConvertValue := t.constructorBody(decl)
+	sourceParamDoc := t.Indent + "/** @param {any} [source] */\n"
+	createConstructor := t.CreateConstructor || t.CreateFromMethod
+	if t.CreateFromMethod {
+		result += fmt.Sprintf("\n%s%sstatic createFrom(source = {}) {\n", sourceParamDoc, t.Indent)
+		result += fmt.Sprintf("%s%sreturn new %s(source);\n", t.Indent, t.Indent, entityName)
+		result += fmt.Sprintf("%s}\n", t.Indent)
+	}
+	if createConstructor {
+		result += fmt.Sprintf("\n%s%sconstructor(source = {}) {\n", sourceParamDoc, t.Indent)
+		result += t.Indent + t.Indent + "if ('string' === typeof source) source = JSON.parse(source);\n"
+		result += constructorBody + "\n"
+		result += fmt.Sprintf("%s}\n", t.Indent)
+	}
+	if needsConvertValue && createConstructor {
+		result += "\n" + indentLines(strings.ReplaceAll(jsConvertValuesFunc, "\t", t.Indent), 1) + "\n"
+	}
+	result += t.customCodeBlock(e.customCode, entityName)
+
+	return result + "}" + customCodeAfter(e.customCode, entityName, true), nil
+}
+
+// jsField returns the lines of a class field with its type in a JSDoc comment, and the same field as a
+// `@property` for the JSDoc typedef (used instead of interfaces).
+func (t *TypeScriptify) jsField(fld *ir.Field) ([]string, string) {
+	fldType, doc := t.tsType(fld.Type), fieldDoc(fld)
+
+	fieldType := fldType
+	if fld.Optional {
+		fieldType += " | undefined"
+	}
+	tags := "@type {" + fieldType + "}"
+	if t.readOnly(fld) {
+		tags = "@readonly\n" + tags
+	}
+	var lines []string
+	for _, line := range tsDocComment(strings.TrimSpace(doc + "\n" + tags)) {
+		lines = append(lines, t.Indent+line)
+	}
+	lines = append(lines, t.Indent+fld.Name+";")
+
+	property := fld.Name
+	if fld.Optional {
+		property = "[" + fld.Name + "]"
+	}
+	line := fmt.Sprintf("@property {%s} %s", fldType, property)
+	if description := jsDocDescription(doc); description != "" {
+		line += " - " + description
+	}
+	return lines, line
+}
+
+// jsTypedefComment creates the JSDoc typedef for a struct.
+func jsTypedefComment(name, doc string, properties []string) string {
+	lines := []string{}
+	if doc != "" {
+		lines = append(lines, doc)
+	}
+	lines = append(lines, "@typedef {Object} "+name)
+	lines = append(lines, properties...)
+	return strings.Join(tsDocComment(strings.Join(lines, "\n")), "\n")
+}
+
+// jsEnum creates a frozen object with the enum values, typed with a JSDoc `@enum`.
+func (t *TypeScriptify) jsEnum(decl *ir.Declaration) string {
+	enumType := "number"
+	if len(decl.Members) > 0 {
+		if _, isString := decl.Members[0].Value.(string); isString {
+			enumType = "string"
+		}
+	}
+
+	result := docCommentPrefix(strings.TrimSpace(decl.Doc + "\n@enum {" + enumType + "}"))
+	if !t.DontExport {
+		result += "export "
+	}
+	result += "const " + decl.Name + " = Object.freeze({\n"
+	for _, member := range decl.Members {
+		result += fmt.Sprintf("%s%s: %s,\n", t.Indent, member.Name, enumMemberValue(member))
+	}
+	result += "});"
+
+	return result
+}
+
+// jsDocDescription returns the doc lines (without tags) joined in one line.
+func jsDocDescription(doc string) string {
+	var description []string
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "@") {
+			continue
+		}
+		description = append(description, line)
+	}
+	return strings.Join(description, " ")
+}
@@ -0,0 +1,118 @@
+package typescriptify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJavaScriptClasses(t *testing.T) {
+	t.Parallel()
+	type Tag struct {
+		Name string `json:"name" ts_doc:"Tag name"`
+	}
+	type Post struct {
+		Title   string         `json:"title"`
+		Tags    []Tag          `json:"tags"`
+		Author  *Tag           `json:"author"`
+		Counts  map[string]int `json:"counts"`
+		Weekday Weekday        `json:"weekday"`
+	}
+
+	converter := New().
+		Add(Post{}).
+		AddEnumWithDoc(allWeekdaysV1, "Day of the week").
+		WithJavaScript(true).
+		WithIndent("\t").
+		WithBackupDir("")
+
+	desiredResult := `// @ts-check
+
+/**
+ * Day of the week
+ * @enum {number}
+ */
+export const Weekday = Object.freeze({
+	SUNDAY: 0,
+	MONDAY: 1,
+	TUESDAY: 2,
+	WEDNESDAY: 3,
+	THURSDAY: 4,
+	FRIDAY: 5,
+	SATURDAY: 6,
+});
+export class Tag {
+	/**
+	 * Tag name
+	 * @type {string}
+	 */
+	name;
+
+	/** @param {any} [source] */
+	constructor(source = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.name = source["name"];
+	}
+}
+export class Post {
+	/** @type {string} */
+	title;
+	/** @type {Tag[]} */
+	tags;
+	/** @type {Tag | undefined} */
+	author;
+	/** @type {{[key: string]: number}} */
+	counts;
+	/** @type {Weekday} */
+	weekday;
+
+	/** @param {any} [source] */
+	constructor(source = {}) {
+		if ('string' === typeof source) source = JSON.parse(source);
+		this.title = source["title"];
+		this.tags = this.convertValues(source["tags"], Tag);
+		this.author = this.convertValues(source["author"], Tag);
+		this.counts = source["counts"];
+		this.weekday = source["weekday"];
+	}
+
+` + indentLines(jsConvertValuesFunc, 1) + `
+}`
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, desiredResult, strings.TrimSpace(typeScriptCode))
+}
+
+func TestJavaScriptTypedefs(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(NewStruct(Address{}).WithDoc("An address")).
+		WithJavaScript(true).
+		WithInterface(true).
+		WithBackupDir("")
+
+	desiredResult := `// @ts-check
+
+/**
+ * An address
+ * @typedef {Object} Address
+ * @property {number} duration
+ * @property {string} [text]
+ * @property {string} [Text2]
+ */`
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, desiredResult, strings.TrimSpace(typeScriptCode))
+}
+
+func TestJavaScriptDeclaration(t *testing.T) {
+	t.Parallel()
+	_, err := New().
+		AddType(reflect.TypeOf(Address{})).
+		WithJavaScript(true).
+		WithDeclaration(true).
+		Convert(nil)
+	assert.NotNil(t, err)
+}
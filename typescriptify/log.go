@@ -0,0 +1,47 @@
+package typescriptify
+
+import (
+	"context"
+	"log/slog"
+)
+
+// discardLogger is the logger used if Logger is nil.
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler ignoring all records.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+func (t *TypeScriptify) logger() *slog.Logger {
+	if t.Logger == nil {
+		return discardLogger
+	}
+	return t.Logger
+}
+
+// logType logs (at debug level) the conversion of a struct or an enum.
+func (t *TypeScriptify) logType(depth int, msg, goType string) {
+	t.logger().Debug(msg, slog.String("type", goType), slog.Int("depth", depth))
+}
+
+// logField logs (at debug level) the conversion of a field, with its Go type if not empty.
+func (t *TypeScriptify) logField(depth int, kind, structName, fieldName, goType string) {
+	attrs := []any{slog.String("type", structName), slog.String("field", fieldName), slog.String("kind", kind), slog.Int("depth", depth)}
+	if goType != "" {
+		attrs = append(attrs, slog.String("goType", goType))
+	}
+	t.logger().Debug("Converting field", attrs...)
+}
+
+// logDiagnostic logs a warning (or error) found in the models.
+func (t *TypeScriptify) logDiagnostic(diagnostic Diagnostic) {
+	level := slog.LevelWarn
+	if diagnostic.Severity == SeverityError {
+		level = slog.LevelError
+	}
+	t.logger().Log(context.Background(), level, diagnostic.Err.Error(), slog.String("type", diagnostic.Type), slog.String("field", diagnostic.Path))
+}
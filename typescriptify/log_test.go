@@ -0,0 +1,42 @@
+package typescriptify
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	type Logged struct {
+		Name    string                 `json:"name"`
+		Tags    []string               `json:"tags"`
+		Options struct{ Enabled bool } `json:"options"`
+	}
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	_, err := New().WithLogger(logger).Add(Logged{}).Convert(nil)
+	assert.Nil(t, err)
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &record))
+		delete(record, "time")
+		records = append(records, record)
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "DEBUG", "msg": "Converting type", "type": "typescriptify.Logged", "depth": 0.0},
+		{"level": "DEBUG", "msg": "Converting field", "type": "Logged", "field": "Name", "kind": "simple", "depth": 0.0},
+		{"level": "DEBUG", "msg": "Converting field", "type": "Logged", "field": "Tags", "kind": "slice", "depth": 0.0, "goType": "[]string"},
+		{"level": "DEBUG", "msg": "Converting field", "type": "Logged", "field": "Options", "kind": "struct", "depth": 0.0, "goType": "struct { Enabled bool }"},
+		{"level": "DEBUG", "msg": "Converting type", "type": "struct { Enabled bool }", "depth": 1.0},
+		{"level": "DEBUG", "msg": "Converting field", "type": "", "field": "Enabled", "kind": "simple", "depth": 1.0},
+		{"level": "WARN", "msg": ErrUnnamedStruct.Error(), "type": "typescriptify.Logged", "field": "Options"},
+	}, records)
+}
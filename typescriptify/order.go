@@ -0,0 +1,117 @@
+package typescriptify
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+// DeclarationOrder is the order of the declarations in the output, see WithOrder.
+type DeclarationOrder string
+
+const (
+	// OrderInsertion is the order in which types are added, with dependencies (in field order) before the
+	// types using them. This is the default.
+	OrderInsertion DeclarationOrder = "insertion"
+	// OrderTopological puts dependencies before the types using them, and is alphabetical otherwise. Types
+	// depending on each other are ordered alphabetically.
+	OrderTopological DeclarationOrder = "topological"
+	// OrderAlphabetical sorts declarations by name.
+	OrderAlphabetical DeclarationOrder = "alphabetical"
+)
+
+// orderDeclarations returns the declarations in the given order.
+func orderDeclarations(decls []*ir.Declaration, order DeclarationOrder) ([]*ir.Declaration, error) {
+	switch order {
+	case "", OrderInsertion:
+		return decls, nil
+	case OrderAlphabetical:
+		result := append([]*ir.Declaration{}, decls...)
+		sort.SliceStable(result, func(i, j int) bool {
+			return declarationLess(result[i], result[j])
+		})
+		return result, nil
+	case OrderTopological:
+		return topologicalOrder(decls), nil
+	}
+	return nil, fmt.Errorf("invalid declaration order %q", order)
+}
+
+// topologicalOrder puts declarations before the ones referencing them. From the declarations without
+// (remaining) dependencies the alphabetically first is taken, and when there are only cycles left, the
+// alphabetically first declaration of a cycle.
+func topologicalOrder(decls []*ir.Declaration) []*ir.Declaration {
+	byName := map[string]*ir.Declaration{}
+	for _, decl := range decls {
+		byName[decl.Name] = decl
+	}
+
+	dependencies := map[*ir.Declaration]map[*ir.Declaration]bool{}
+	dependents := map[*ir.Declaration][]*ir.Declaration{}
+	for _, decl := range decls {
+		dependencies[decl] = map[*ir.Declaration]bool{}
+		for _, ref := range decl.References() {
+			if dep, found := byName[ref]; found && dep != decl && !dependencies[decl][dep] {
+				dependencies[decl][dep] = true
+				dependents[dep] = append(dependents[dep], decl)
+			}
+		}
+	}
+
+	remaining := append([]*ir.Declaration{}, decls...)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return declarationLess(remaining[i], remaining[j])
+	})
+
+	result := make([]*ir.Declaration, 0, len(decls))
+	for len(remaining) > 0 {
+		// The first declaration without dependencies, or the first one in a cycle:
+		next := -1
+		for n, decl := range remaining {
+			if len(dependencies[decl]) == 0 {
+				next = n
+				break
+			}
+		}
+		for n := 0; next < 0; n++ {
+			if inCycle(remaining[n], dependencies) {
+				next = n
+			}
+		}
+		decl := remaining[next]
+		remaining = append(remaining[:next], remaining[next+1:]...)
+		result = append(result, decl)
+		for _, dependent := range dependents[decl] {
+			delete(dependencies[dependent], decl)
+		}
+	}
+	return result
+}
+
+// inCycle returns true if a declaration (indirectly) depends on itself.
+func inCycle(decl *ir.Declaration, dependencies map[*ir.Declaration]map[*ir.Declaration]bool) bool {
+	visited := map[*ir.Declaration]bool{}
+	stack := []*ir.Declaration{decl}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for dep := range dependencies[current] {
+			if dep == decl {
+				return true
+			}
+			if !visited[dep] {
+				visited[dep] = true
+				stack = append(stack, dep)
+			}
+		}
+	}
+	return false
+}
+
+func declarationLess(a, b *ir.Declaration) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.GoType < b.GoType
+}
@@ -0,0 +1,67 @@
+package typescriptify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Graph struct {
+	Root    *Node   `json:"root"`
+	Weekday Weekday `json:"weekday"`
+}
+
+type Node struct {
+	Edges []Edge `json:"edges"`
+	Label Label  `json:"label"`
+}
+
+type Edge struct {
+	From *Node `json:"from"`
+	To   *Node `json:"to"`
+}
+
+type Label struct {
+	Text string `json:"text"`
+}
+
+func declarationNames(t *testing.T, converter *TypeScriptify) []string {
+	model, err := converter.Model()
+	assert.Nil(t, err)
+	var names []string
+	for _, decl := range model.Declarations {
+		names = append(names, decl.Name)
+	}
+	return names
+}
+
+func TestDeclarationOrder(t *testing.T) {
+	t.Parallel()
+
+	for order, expected := range map[DeclarationOrder][]string{
+		"":                {"Weekday", "Label", "Edge", "Node", "Graph"},
+		OrderInsertion:    {"Weekday", "Label", "Edge", "Node", "Graph"},
+		OrderTopological:  {"Label", "Weekday", "Edge", "Node", "Graph"},
+		OrderAlphabetical: {"Edge", "Graph", "Label", "Node", "Weekday"},
+	} {
+		converter := New().Add(Graph{}).AddEnum(allWeekdaysV1).WithOrder(order).WithBackupDir("")
+		assert.Equal(t, expected, declarationNames(t, converter), "order %q", order)
+	}
+}
+
+func TestDeclarationOrderIndependentOfAddOrder(t *testing.T) {
+	t.Parallel()
+
+	for _, order := range []DeclarationOrder{OrderTopological, OrderAlphabetical} {
+		converter1 := New().Add(Graph{}).Add(Label{}).AddEnum(allWeekdaysV1).WithOrder(order)
+		converter2 := New().Add(Label{}).Add(Edge{}).Add(Graph{}).AddEnum(allWeekdaysV1).WithOrder(order)
+		assert.Equal(t, declarationNames(t, converter1), declarationNames(t, converter2), "order %q", order)
+	}
+}
+
+func TestInvalidDeclarationOrder(t *testing.T) {
+	t.Parallel()
+
+	_, err := New().Add(Graph{}).WithOrder("random").Convert(nil)
+	assert.NotNil(t, err)
+}
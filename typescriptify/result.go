@@ -0,0 +1,48 @@
+package typescriptify
+
+import (
+	"io"
+	"strings"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+// ConvertResult is the converted code, with the code of every declaration.
+type ConvertResult struct {
+	Header       string // Code before the declarations, i.e. imports
+	Declarations []ConvertedDeclaration
+	Footer       string // Code after the declarations, i.e. custom code at the end of the file
+}
+
+// ConvertedDeclaration is the code of one enum, class or interface.
+type ConvertedDeclaration struct {
+	Name   string  // TypeScript name
+	GoType string  // Converted Go type, i.e. `models.Person`
+	Kind   ir.Kind // Struct or enum
+	Code   string
+}
+
+// String returns the code of all declarations, as returned by Convert.
+func (r *ConvertResult) String() string {
+	var sb strings.Builder
+	_, _ = r.WriteTo(&sb)
+	return sb.String()
+}
+
+// WriteTo writes the code of all declarations to w.
+func (r *ConvertResult) WriteTo(w io.Writer) (int64, error) {
+	written, err := io.WriteString(w, r.Header)
+	total := int64(written)
+	for _, decl := range r.Declarations {
+		if err != nil {
+			break
+		}
+		written, err = io.WriteString(w, "\n"+decl.Code)
+		total += int64(written)
+	}
+	if err == nil && r.Footer != "" {
+		written, err = io.WriteString(w, "\n"+r.Footer)
+		total += int64(written)
+	}
+	return total, err
+}
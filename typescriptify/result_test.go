@@ -0,0 +1,51 @@
+package typescriptify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertTo(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Holliday{}).
+		AddEnum(allWeekdaysV1).
+		WithConstructor(false).
+		WithIndent("\t").
+		WithBackupDir("")
+
+	var buf bytes.Buffer
+	result, err := converter.ConvertTo(&buf)
+	assert.Nil(t, err)
+
+	converted, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, converted, buf.String())
+	assert.Equal(t, converted, result.String())
+
+	assert.Len(t, result.Declarations, 2)
+	assert.Equal(t, "Weekday", result.Declarations[0].Name)
+	assert.Equal(t, "typescriptify.Weekday", result.Declarations[0].GoType)
+	assert.Equal(t, ir.KindEnum, result.Declarations[0].Kind)
+	assert.True(t, strings.HasPrefix(result.Declarations[0].Code, "export enum Weekday {\n"))
+	assert.Equal(t, ConvertedDeclaration{
+		Name:   "Holliday",
+		GoType: "typescriptify.Holliday",
+		Kind:   ir.KindStruct,
+		Code:   "export class Holliday {\n\tname: string;\n\tweekday: Weekday;\n}",
+	}, result.Declarations[1])
+}
+
+func TestConvertToWritesNothingOnError(t *testing.T) {
+	t.Parallel()
+	converter := New().Add(Holliday{}).WithJavaScript(true).WithDeclaration(true)
+
+	var buf bytes.Buffer
+	_, err := converter.ConvertTo(&buf)
+	assert.NotNil(t, err)
+	assert.Empty(t, buf.String())
+}
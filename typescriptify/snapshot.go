@@ -0,0 +1,50 @@
+package typescriptify
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+// SaveSnapshot writes the model (see Model) as JSON, to be compared with later versions of the models
+// using Diff.
+func (t *TypeScriptify) SaveSnapshot(fileName string) error {
+	model, err := t.Model()
+	if err != nil {
+		return err
+	}
+	byts, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, append(byts, '\n'), 0644)
+}
+
+// LoadSnapshot reads a model saved with SaveSnapshot.
+func LoadSnapshot(fileName string) (*ir.Model, error) {
+	byts, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	model := new(ir.Model)
+	if err := json.Unmarshal(byts, model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// Diff compares a snapshot saved with SaveSnapshot with the current model, and reports breaking
+// (removed declarations, fields and enum members, changed types, optional fields made required) and
+// non-breaking changes.
+func (t *TypeScriptify) Diff(snapshotFile string) (ir.Report, error) {
+	snapshot, err := LoadSnapshot(snapshotFile)
+	if err != nil {
+		return ir.Report{}, err
+	}
+	model, err := t.Model()
+	if err != nil {
+		return ir.Report{}, err
+	}
+	return ir.Compare(snapshot, model), nil
+}
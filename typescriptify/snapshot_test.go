@@ -0,0 +1,36 @@
+package typescriptify
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	t.Parallel()
+	snapshotFile := filepath.Join(t.TempDir(), "snapshot.json")
+
+	converter := New().Add(Holliday{}).AddEnum(allWeekdaysV2).WithBackupDir("")
+	assert.Nil(t, converter.SaveSnapshot(snapshotFile))
+
+	report, err := converter.Diff(snapshotFile)
+	assert.Nil(t, err)
+	assert.Equal(t, ir.Report{}, report)
+
+	optional := TagAll(reflect.TypeOf(Holliday{}), []string{"omitempty"})
+	converter = New().AddTypeWithName(optional, "Holliday").WithBackupDir("")
+	report, err = converter.Diff(snapshotFile)
+	assert.Nil(t, err)
+	assert.Equal(t, ir.Report{Breaking: true, Changes: []ir.Change{
+		{Kind: ir.DeclarationRemoved, Breaking: true, Path: "Weekday"},
+		{Kind: ir.FieldType, Breaking: true, Path: "Holliday.weekday", Old: "Weekday", New: "number"},
+		{Kind: ir.FieldOptional, Path: "Holliday.name"},
+		{Kind: ir.FieldOptional, Path: "Holliday.weekday"},
+	}}, report)
+
+	_, err = converter.Diff(filepath.Join(t.TempDir(), "missing.json"))
+	assert.NotNil(t, err)
+}
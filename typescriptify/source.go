@@ -0,0 +1,192 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+// GoType is a Go type analyzed by the converter. The types added with Add are analyzed with reflection, the
+// static package implements GoType with go/types to analyze types from source (see AddStaticStruct).
+type GoType interface {
+	// ID identifies the type, types with the same ID are converted once. It must be comparable. Types of
+	// different implementations are the same if they have the same package path and Go type (String).
+	ID() any
+	Kind() reflect.Kind
+	Name() string    // Name of a named type (like reflect.Type.Name), or an empty string
+	PkgPath() string // Package path of a named type (like reflect.Type.PkgPath)
+	String() string  // Go type with the package name, i.e. `models.Person`
+	Elem() GoType    // Element type of a pointer, slice, array or map
+	Key() GoType     // Key type of a map
+	// Generic returns true for generic types which can't be converted (reflection converts instantiated
+	// generic types with their names).
+	Generic() bool
+	// Fields returns the fields of a struct, with the fields of embedded structs (which are promoted).
+	Fields() ([]GoField, error)
+}
+
+// GoField is a field of a struct, see GoType.Fields.
+type GoField struct {
+	Name     string
+	PkgPath  string // Package path of an unexported field, empty for exported fields (like reflect.StructField)
+	Tag      reflect.StructTag
+	Type     GoType
+	Promoted bool // Field of an embedded struct
+}
+
+// StaticStruct is a struct analyzed from source (see the static package), it is converted like the structs
+// added with Add.
+type StaticStruct struct {
+	Type GoType
+	// JSONOptions, if set, replace the options of the json tags of the struct fields (but not of the structs
+	// used in them, or of the promoted fields), like TagAll.
+	JSONOptions []string
+	// FieldOptions are the options of the fields of a type (given by package path and name, i.e. `time.Time`),
+	// like StructType.FieldOptions.
+	FieldOptions map[string]TypeOptions
+}
+
+// staticEnum is an enum added with AddStaticEnum.
+type staticEnum struct {
+	typ     GoType
+	members []*ir.EnumMember
+}
+
+// addedStruct is a struct added with Add (or AddStaticStruct), with its options.
+type addedStruct struct {
+	typ         GoType
+	name        string // Declaration name of an anonymous struct
+	doc         string
+	jsonOptions []string
+	// fieldOptions returns the options of the fields of a type set for the struct.
+	fieldOptions func(fieldType GoType) (TypeOptions, bool)
+}
+
+// AddStaticStruct adds a struct analyzed from source, see the static package. Generic structs are not
+// supported, errors are returned by Model (and the conversion).
+func (t *TypeScriptify) AddStaticStruct(s StaticStruct) *TypeScriptify {
+	if s.Type.Kind() != reflect.Struct {
+		t.errs = append(t.errs, fmt.Errorf("%s is not a struct", s.Type))
+		return t
+	}
+	if s.Type.Generic() {
+		t.errs = append(t.errs, fmt.Errorf("%s: %w", s.Type, ErrGenericStruct))
+		return t
+	}
+	t.staticStructs = append(t.staticStructs, s)
+	return t
+}
+
+// AddStaticEnum adds an enum analyzed from source (see the static package), with its members (like the
+// values of AddEnum). Errors are returned by Model (and the conversion).
+func (t *TypeScriptify) AddStaticEnum(typ GoType, members []*ir.EnumMember) *TypeScriptify {
+	if typ.Name() == "" {
+		t.errs = append(t.errs, &EnumError{Type: typ.String(), Err: fmt.Errorf("enum values must have a named type")})
+		return t
+	}
+	if len(members) == 0 {
+		t.errs = append(t.errs, &EnumError{Type: typ.String(), Err: fmt.Errorf("no enum values")})
+		return t
+	}
+	t.staticEnums = append(t.staticEnums, staticEnum{typ: typ, members: members})
+	return t
+}
+
+// addedStructs returns the structs added with Add and AddStaticStruct.
+func (t *TypeScriptify) addedStructs() []addedStruct {
+	var result []addedStruct
+	for _, strctTyp := range t.structTypes {
+		fieldOptions := strctTyp.FieldOptions
+		result = append(result, addedStruct{
+			typ:  reflectType{strctTyp.Type},
+			name: strctTyp.Name,
+			doc:  strctTyp.Doc,
+			fieldOptions: func(fieldType GoType) (TypeOptions, bool) {
+				for typ, opts := range fieldOptions {
+					if sameType(fieldType, reflectType{typ}) {
+						return opts, true
+					}
+				}
+				return TypeOptions{}, false
+			},
+		})
+	}
+	for _, strct := range t.staticStructs {
+		fieldOptions := strct.FieldOptions
+		result = append(result, addedStruct{
+			typ:         strct.Type,
+			jsonOptions: strct.JSONOptions,
+			fieldOptions: func(fieldType GoType) (TypeOptions, bool) {
+				if fieldType.Name() == "" {
+					return TypeOptions{}, false
+				}
+				opts, found := fieldOptions[fieldType.PkgPath()+"."+fieldType.Name()]
+				return opts, found
+			},
+		})
+	}
+	return result
+}
+
+// addedStruct returns the first added struct of a type, or nil.
+func (t *TypeScriptify) addedStruct(typ GoType) *addedStruct {
+	for i := range t.added {
+		if t.added[i].typ.ID() == typ.ID() {
+			return &t.added[i]
+		}
+	}
+	return nil
+}
+
+// sameType returns true if two types are the same, types of different implementations (i.e. a field type
+// analyzed from source and a type set with ManageType) are matched by package path and Go type.
+func sameType(a, b GoType) bool {
+	if a.ID() == b.ID() {
+		return true
+	}
+	_, aReflect := a.(reflectType)
+	_, bReflect := b.(reflectType)
+	return aReflect != bReflect && a.PkgPath() == b.PkgPath() && a.String() == b.String()
+}
+
+// reflectType is a GoType analyzed with reflection.
+type reflectType struct {
+	typ reflect.Type
+}
+
+func (r reflectType) ID() any            { return r.typ }
+func (r reflectType) Kind() reflect.Kind { return r.typ.Kind() }
+func (r reflectType) Name() string       { return r.typ.Name() }
+func (r reflectType) PkgPath() string    { return structPackage(r.typ) }
+func (r reflectType) String() string     { return r.typ.String() }
+func (r reflectType) Elem() GoType       { return reflectType{r.typ.Elem()} }
+func (r reflectType) Key() GoType        { return reflectType{r.typ.Key()} }
+func (r reflectType) Generic() bool      { return false }
+
+func (r reflectType) Fields() ([]GoField, error) {
+	return reflectFields(r.typ, false), nil
+}
+
+// reflectFields returns the fields of a struct with the fields of embedded structs.
+func reflectFields(typeOf reflect.Type, promoted bool) []GoField {
+	if typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+	if typeOf.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []GoField
+	for i := 0; i < typeOf.NumField(); i++ {
+		f := typeOf.Field(i)
+
+		kind := f.Type.Kind()
+		if f.Anonymous && (kind == reflect.Struct || kind == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct) {
+			fields = append(fields, reflectFields(f.Type, true)...)
+		} else {
+			fields = append(fields, GoField{Name: f.Name, PkgPath: f.PkgPath, Tag: f.Tag, Type: reflectType{f.Type}, Promoted: promoted})
+		}
+	}
+	return fields
+}
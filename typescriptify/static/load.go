@@ -0,0 +1,101 @@
+package static
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+)
+
+// loadedPackage is a package parsed and type-checked from source.
+type loadedPackage struct {
+	path  string
+	types *types.Package
+	info  *types.Info
+	files []*ast.File
+}
+
+// importer parses and type-checks the imported packages from source (with go/build, in the module of its
+// directory), without function bodies.
+type importer struct {
+	ctxt     build.Context
+	fset     *token.FileSet
+	packages map[string]*types.Package
+}
+
+func newImporter(dir string) (*importer, error) {
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	ctxt := build.Default
+	ctxt.Dir = dir
+	return &importer{ctxt: ctxt, fset: token.NewFileSet(), packages: map[string]*types.Package{}}, nil
+}
+
+func (imp *importer) Import(path string) (*types.Package, error) {
+	return imp.ImportFrom(path, imp.ctxt.Dir, 0)
+}
+
+// ImportFrom type-checks an imported package, its type errors are ignored (only the declarations used by the
+// models matter).
+func (imp *importer) ImportFrom(path, dir string, _ types.ImportMode) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	bp, err := imp.ctxt.Import(path, dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	if pkg, found := imp.packages[bp.ImportPath]; found {
+		return pkg, nil
+	}
+	files, err := imp.parse(bp, 0)
+	if err != nil {
+		return nil, err
+	}
+	conf := types.Config{Importer: imp, FakeImportC: true, IgnoreFuncBodies: true, Error: func(error) {}}
+	pkg, _ := conf.Check(bp.ImportPath, imp.fset, files, nil)
+	imp.packages[bp.ImportPath] = pkg
+	return pkg, nil
+}
+
+// load parses and type-checks the package with the models, with its syntax and type information.
+func (imp *importer) load(path string) (*loadedPackage, error) {
+	bp, err := imp.ctxt.Import(path, imp.ctxt.Dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	files, err := imp.parse(bp, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	pkg := &loadedPackage{
+		path:  bp.ImportPath,
+		info:  &types.Info{Types: map[ast.Expr]types.TypeAndValue{}, Defs: map[*ast.Ident]types.Object{}},
+		files: files,
+	}
+	conf := types.Config{Importer: imp, FakeImportC: true}
+	if pkg.types, err = conf.Check(bp.ImportPath, imp.fset, files, pkg.info); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// parse parses the (non test) Go files of a package.
+func (imp *importer) parse(bp *build.Package, mode parser.Mode) ([]*ast.File, error) {
+	var files []*ast.File
+	for _, fileName := range append(bp.GoFiles, bp.CgoFiles...) {
+		file, err := parser.ParseFile(imp.fset, filepath.Join(bp.Dir, fileName), nil, mode|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
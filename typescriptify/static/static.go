@@ -0,0 +1,183 @@
+// Package static adds the models of a Go package to a converter without compiling them: the package is parsed
+// and type-checked from source (with go/build and go/types), and the model is the same as for structs added
+// with typescriptify.TypeScriptify.Add.
+//
+// Options set by tags, ManageTypeName, ManageType and Package.FieldOptions are applied. Enums must be variables
+// initialized with constant Value and TSName fields (TSName methods can't be called statically), and generic
+// structs are not supported.
+package static
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify"
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+// ErrTSNameMethod is the error for enum values which are not struct literals, their TSName methods can't be
+// called statically.
+var ErrTSNameMethod = errors.New("enum values must be structs with Value and TSName fields, TSName methods can't be called statically")
+
+// Package are structs of a Go package analyzed from source, see AddPackage.
+type Package struct {
+	Dir   string   // Directory (in a module) where the package is resolved, the current directory if empty
+	Path  string   // Import path of the package
+	Names []string // Names of the structs
+	Enums []string // Names of variables with enum values (see AddEnum), only structs with Value and TSName fields
+	// JSONOptions, if set, replace the options of the json tags of the struct fields (but not of the structs
+	// used in them), like TagAll.
+	JSONOptions []string
+	// FieldOptions are the options of the fields of a type (given by package path and name, i.e. `time.Time`)
+	// in the structs, like typescriptify.StructType.FieldOptions.
+	FieldOptions map[string]typescriptify.TypeOptions
+}
+
+// AddPackage adds the structs and enums of a Go package to the converter.
+func AddPackage(t *typescriptify.TypeScriptify, p Package) error {
+	imp, err := newImporter(p.Dir)
+	if err != nil {
+		return err
+	}
+	pkg, err := imp.load(p.Path)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", p.Path, err)
+	}
+
+	for _, name := range p.Enums {
+		typ, members, err := enumValues(pkg, name)
+		if err != nil {
+			return err
+		}
+		t.AddStaticEnum(goType{typ}, members)
+	}
+	for _, name := range p.Names {
+		obj, is := pkg.types.Scope().Lookup(name).(*types.TypeName)
+		if !is {
+			return fmt.Errorf("type %s not found in %s", name, p.Path)
+		}
+		if _, is := obj.Type().Underlying().(*types.Struct); !is {
+			return fmt.Errorf("%s.%s is not a struct", pkg.types.Name(), name)
+		}
+		if named, is := obj.Type().(*types.Named); is && named.TypeParams().Len() > 0 {
+			return fmt.Errorf("%s.%s: %w", pkg.types.Name(), name, typescriptify.ErrGenericStruct)
+		}
+		t.AddStaticStruct(typescriptify.StaticStruct{Type: goType{obj.Type()}, JSONOptions: p.JSONOptions, FieldOptions: p.FieldOptions})
+	}
+	return nil
+}
+
+// enumValues returns the type and members of the enum defined by the values in a variable (a slice of structs
+// with Value and TSName fields). The values must be constants.
+func enumValues(pkg *loadedPackage, name string) (types.Type, []*ir.EnumMember, error) {
+	obj, is := pkg.types.Scope().Lookup(name).(*types.Var)
+	if !is {
+		return nil, nil, fmt.Errorf("variable %s not found in %s", name, pkg.path)
+	}
+	lit, is := varValue(pkg, obj).(*ast.CompositeLit)
+	if !is {
+		return nil, nil, fmt.Errorf("%s.%s must be initialized with a slice of enum values", pkg.types.Name(), name)
+	}
+
+	var typ types.Type
+	var members []*ir.EnumMember
+	for _, elt := range lit.Elts {
+		value, tsName, err := enumElement(pkg, elt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s.%s: %w", pkg.types.Name(), name, err)
+		}
+		if typ == nil {
+			typ = value.Type
+		} else if !types.Identical(typ, value.Type) {
+			return nil, nil, fmt.Errorf("%s.%s: values of different types %s and %s", pkg.types.Name(), name, typ, value.Type)
+		}
+		members = append(members, &ir.EnumMember{Name: tsName, Value: enumValue(value)})
+	}
+	if typ == nil {
+		return nil, nil, fmt.Errorf("%s.%s has no enum values", pkg.types.Name(), name)
+	}
+	if _, named := typ.(*types.Named); !named {
+		return nil, nil, fmt.Errorf("%s.%s: enum values must have a named type, not %s", pkg.types.Name(), name, typ)
+	}
+	return typ, members, nil
+}
+
+// varValue returns the expression initializing a package variable, or nil.
+func varValue(pkg *loadedPackage, obj *types.Var) ast.Expr {
+	for _, file := range pkg.files {
+		for _, decl := range file.Decls {
+			genDecl, is := decl.(*ast.GenDecl)
+			if !is || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, ident := range valueSpec.Names {
+					if pkg.info.Defs[ident] == obj && i < len(valueSpec.Values) {
+						return unparen(valueSpec.Values[i])
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// unparen returns the expression without enclosing parentheses.
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, is := expr.(*ast.ParenExpr)
+		if !is {
+			return expr
+		}
+		expr = paren.X
+	}
+}
+
+// enumElement returns the (constant) Value and TSName of an enum value struct.
+func enumElement(pkg *loadedPackage, elt ast.Expr) (types.TypeAndValue, string, error) {
+	lit, is := unparen(elt).(*ast.CompositeLit)
+	if !is {
+		return types.TypeAndValue{}, "", ErrTSNameMethod
+	}
+	strct, is := pkg.info.TypeOf(lit).Underlying().(*types.Struct)
+	if !is {
+		return types.TypeAndValue{}, "", ErrTSNameMethod
+	}
+
+	fields := map[string]ast.Expr{}
+	for i, expr := range lit.Elts {
+		if kv, is := expr.(*ast.KeyValueExpr); is {
+			fields[kv.Key.(*ast.Ident).Name] = kv.Value
+		} else if i < strct.NumFields() {
+			fields[strct.Field(i).Name()] = expr
+		}
+	}
+
+	value, tsName := pkg.info.Types[fields["Value"]], pkg.info.Types[fields["TSName"]]
+	if value.Value == nil || tsName.Value == nil || tsName.Value.Kind() != constant.String {
+		return types.TypeAndValue{}, "", fmt.Errorf("enum values must have constant Value and TSName fields")
+	}
+	return value, constant.StringVal(tsName.Value), nil
+}
+
+// enumValue converts a constant enum value to a string or number, like the values of AddEnum.
+func enumValue(value types.TypeAndValue) interface{} {
+	basic, _ := value.Type.Underlying().(*types.Basic)
+	switch {
+	case value.Value.Kind() == constant.String:
+		return constant.StringVal(value.Value)
+	case basic != nil && basic.Info()&types.IsUnsigned != 0:
+		val, _ := constant.Uint64Val(value.Value)
+		return val
+	case basic != nil && basic.Info()&types.IsInteger != 0:
+		val, _ := constant.Int64Val(value.Value)
+		return val
+	}
+	val, _ := constant.Float64Val(constant.ToFloat(value.Value))
+	return val
+}
@@ -0,0 +1,173 @@
+package static
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify"
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/static/testdata/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testModelsPackage = "github.com/GoodNotes/typescriptify-golang-structs/typescriptify/static/testdata/models"
+
+func TestAddPackage(t *testing.T) {
+	t.Parallel()
+
+	for _, options := range []struct {
+		name      string
+		configure func(*typescriptify.TypeScriptify) *typescriptify.TypeScriptify
+	}{
+		{name: "classes", configure: func(t *typescriptify.TypeScriptify) *typescriptify.TypeScriptify { return t }},
+		{name: "interfaces", configure: func(t *typescriptify.TypeScriptify) *typescriptify.TypeScriptify {
+			return t.WithInterface(true).WithCamelCaseFields(true, nil)
+		}},
+		{name: "validate", configure: func(t *typescriptify.TypeScriptify) *typescriptify.TypeScriptify {
+			return t.WithValidateTags(true).WithPrefix("API")
+		}},
+		{name: "managed types", configure: func(t *typescriptify.TypeScriptify) *typescriptify.TypeScriptify {
+			return t.ManageTypeName("time.Time", typescriptify.TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)"})
+		}},
+	} {
+		options := options
+		t.Run(options.name, func(t *testing.T) {
+			t.Parallel()
+
+			reflection := options.configure(typescriptify.New().WithBackupDir(""))
+			reflection.Add(models.Tag{}).Add(models.Item{})
+			static := options.configure(typescriptify.New().WithBackupDir(""))
+			err := AddPackage(static, Package{Path: testModelsPackage, Names: []string{"Tag", "Item"}})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			expected, err := reflection.Convert(nil)
+			assert.Nil(t, err)
+			actual, err := static.Convert(nil)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestAddPackageJSONOptions(t *testing.T) {
+	t.Parallel()
+
+	reflection := typescriptify.New().AddTypeWithName(typescriptify.TagAll(reflect.TypeOf(models.Tag{}), []string{"omitempty"}), "Tag")
+	static := typescriptify.New()
+	assert.Nil(t, AddPackage(static, Package{Path: testModelsPackage, Names: []string{"Tag"}, JSONOptions: []string{"omitempty"}}))
+
+	expected, err := reflection.Convert(nil)
+	assert.Nil(t, err)
+	actual, err := static.Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+	assert.Contains(t, actual, "name?: string;")
+}
+
+func TestAddPackageEnums(t *testing.T) {
+	t.Parallel()
+
+	reflection := typescriptify.New().AddEnum(models.AllLevels).Add(models.Item{})
+	static := typescriptify.New()
+	assert.Nil(t, AddPackage(static, Package{Path: testModelsPackage, Names: []string{"Item"}, Enums: []string{"AllLevels"}}))
+
+	expected, err := reflection.Convert(nil)
+	assert.Nil(t, err)
+	actual, err := static.Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+	assert.Contains(t, actual, `HIGH = "high",`)
+	assert.Contains(t, actual, "level: Level;")
+}
+
+func TestManageTypeName(t *testing.T) {
+	t.Parallel()
+
+	converter := typescriptify.New().Add(models.Item{}).ManageTypeName("time.Time", typescriptify.TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)"})
+	actual, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, actual, "created: Date;")
+	assert.Contains(t, actual, `this.created = new Date(source["created"]);`)
+}
+
+func TestAddPackageModel(t *testing.T) {
+	t.Parallel()
+
+	static := typescriptify.New()
+	assert.Nil(t, AddPackage(static, Package{Path: testModelsPackage, Names: []string{"Item"}}))
+	model, err := static.Model()
+	assert.Nil(t, err)
+
+	item := model.Declaration("Item")
+	if assert.NotNil(t, item) {
+		assert.Equal(t, "models.Item", item.GoType)
+		assert.Equal(t, testModelsPackage, item.Package)
+	}
+	assert.NotNil(t, model.Declaration("Time"))
+}
+
+func TestAddPackageErrors(t *testing.T) {
+	t.Parallel()
+
+	assert.NotNil(t, AddPackage(typescriptify.New(), Package{Path: testModelsPackage, Names: []string{"Missing"}}))
+	assert.NotNil(t, AddPackage(typescriptify.New(), Package{Path: testModelsPackage, Names: []string{"Level"}}))
+	assert.NotNil(t, AddPackage(typescriptify.New(), Package{Path: testModelsPackage, Enums: []string{"LevelLow"}}))
+	assert.NotNil(t, AddPackage(typescriptify.New(), Package{Path: testModelsPackage + "/missing", Names: []string{"Item"}}))
+}
+
+func TestAddPackageTypeOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := typescriptify.TypeOptions{TSType: "Date", TSTransform: "new Date(__VALUE__)"}
+	for _, test := range []struct {
+		name       string
+		reflection *typescriptify.TypeScriptify
+		static     *typescriptify.TypeScriptify
+		pkg        Package
+	}{
+		{
+			name:       "managed type",
+			reflection: typescriptify.New().ManageType(time.Time{}, opts).Add(models.Item{}),
+			static:     typescriptify.New().ManageType(time.Time{}, opts),
+			pkg:        Package{Path: testModelsPackage, Names: []string{"Item"}},
+		},
+		{
+			name:       "field options",
+			reflection: typescriptify.New().Add(typescriptify.NewStruct(models.Item{}).WithFieldOpts(time.Time{}, opts)),
+			static:     typescriptify.New(),
+			pkg:        Package{Path: testModelsPackage, Names: []string{"Item"}, FieldOptions: map[string]typescriptify.TypeOptions{"time.Time": opts}},
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Nil(t, AddPackage(test.static, test.pkg))
+			expected, err := test.reflection.Convert(nil)
+			assert.Nil(t, err)
+			actual, err := test.static.Convert(nil)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, actual)
+			assert.Contains(t, actual, "created: Date;")
+		})
+	}
+}
+
+func TestAddPackageUnsupported(t *testing.T) {
+	t.Parallel()
+
+	err := AddPackage(typescriptify.New(), Package{Path: testModelsPackage, Enums: []string{"AllColors"}})
+	assert.True(t, errors.Is(err, ErrTSNameMethod))
+
+	err = AddPackage(typescriptify.New(), Package{Path: testModelsPackage, Names: []string{"Page"}})
+	assert.True(t, errors.Is(err, typescriptify.ErrGenericStruct))
+
+	converter := typescriptify.New()
+	assert.Nil(t, AddPackage(converter, Package{Path: testModelsPackage, Names: []string{"Catalog"}}))
+	_, err = converter.Convert(nil)
+	assert.True(t, errors.Is(err, typescriptify.ErrGenericStruct))
+	assert.Contains(t, err.Error(), "error: models.Catalog.Page: models.Page[models.Tag]: generic structs are not supported")
+}
@@ -0,0 +1,78 @@
+// Package models contains the structs used to compare the static analysis (see AddPackage) with reflection.
+package models
+
+import "time"
+
+type Level string
+
+const (
+	LevelLow  Level = "low"
+	LevelHigh Level = "high"
+)
+
+var AllLevels = []struct {
+	Value  Level
+	TSName string
+}{
+	{LevelLow, "LOW"},
+	{Value: LevelHigh, TSName: "HIGH"},
+}
+
+type Base struct {
+	ID      int64     `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type Audit struct {
+	Editor string `json:"editor,omitempty"`
+}
+
+type Tag struct {
+	Name  string `json:"name" validate:"required,min=1,max=20"`
+	Color string `json:"color,omitempty" ts_doc:"CSS color"`
+}
+
+type Item struct {
+	Base
+	*Audit
+	Title     string          `json:"title" validate:"required"`
+	Level     Level           `json:"level"`
+	Price     float64         `json:"price" ts_type:"Decimal" ts_transform:"new Decimal(__VALUE__)"`
+	Tags      []Tag           `json:"tags"`
+	TagsByKey map[string]*Tag `json:"tags_by_key"`
+	Matrix    [][]int         `json:"matrix"`
+	Fixed     [3]string       `json:"fixed"`
+	Parent    *Item           `json:"parent"`
+	Extra     interface{}     `json:"extra"`
+	Counts    map[Level]uint8 `json:"counts,omitempty"`
+	Ignored   string          `json:"-"`
+	NoTag     string
+	Options   struct{ Enabled bool } `json:"options"`
+	internal  string
+}
+
+// Page is generic, not supported by the static analysis.
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
+
+type Catalog struct {
+	Page Page[Tag] `json:"page"`
+}
+
+type Color int
+
+const (
+	ColorRed Color = iota
+	ColorBlue
+)
+
+func (c Color) TSName() string {
+	if c == ColorRed {
+		return "RED"
+	}
+	return "BLUE"
+}
+
+// AllColors can only be converted with reflection (TSName methods can't be called statically).
+var AllColors = []Color{ColorRed, ColorBlue}
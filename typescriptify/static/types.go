@@ -0,0 +1,142 @@
+package static
+
+import (
+	"go/types"
+	"reflect"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify"
+)
+
+// goType is a typescriptify.GoType analyzed from source with go/types.
+type goType struct {
+	typ types.Type
+}
+
+func (g goType) ID() any            { return types.TypeString(g.typ, nil) }
+func (g goType) Kind() reflect.Kind { return kind(g.typ) }
+
+// Name returns the name of a named type (without the type arguments of generic types), or an empty string.
+func (g goType) Name() string {
+	if named, is := g.typ.(*types.Named); is {
+		return named.Obj().Name()
+	}
+	if basic, is := g.typ.(*types.Basic); is {
+		return basic.Name()
+	}
+	return ""
+}
+
+func (g goType) PkgPath() string {
+	if named, is := g.typ.(*types.Named); is && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Path()
+	}
+	return ""
+}
+
+// String returns the Go type with the package name (like reflect.Type.String).
+func (g goType) String() string {
+	return types.TypeString(g.typ, func(pkg *types.Package) string {
+		return pkg.Name()
+	})
+}
+
+func (g goType) Elem() typescriptify.GoType {
+	if elem, is := g.typ.Underlying().(interface{ Elem() types.Type }); is {
+		return goType{elem.Elem()}
+	}
+	return nil
+}
+
+func (g goType) Key() typescriptify.GoType {
+	if m, is := g.typ.Underlying().(*types.Map); is {
+		return goType{m.Key()}
+	}
+	return nil
+}
+
+// Generic returns true if a type is generic, or an instantiated generic type.
+func (g goType) Generic() bool {
+	named, is := g.typ.(*types.Named)
+	return is && (named.TypeArgs().Len() > 0 || named.TypeParams().Len() > 0)
+}
+
+func (g goType) Fields() ([]typescriptify.GoField, error) {
+	return fields(g.typ, false), nil
+}
+
+// fields returns the fields of a struct with the fields of embedded structs.
+func fields(typ types.Type, promoted bool) []typescriptify.GoField {
+	if ptr, is := typ.Underlying().(*types.Pointer); is {
+		typ = ptr.Elem()
+	}
+	strct, is := typ.Underlying().(*types.Struct)
+	if !is {
+		return nil
+	}
+
+	var result []typescriptify.GoField
+	for i := 0; i < strct.NumFields(); i++ {
+		f := strct.Field(i)
+		embedded := f.Type()
+		if ptr, is := embedded.Underlying().(*types.Pointer); is {
+			embedded = ptr.Elem()
+		}
+		if f.Embedded() && kind(embedded) == reflect.Struct {
+			result = append(result, fields(embedded, true)...)
+			continue
+		}
+		field := typescriptify.GoField{Name: f.Name(), Tag: reflect.StructTag(strct.Tag(i)), Type: goType{f.Type()}, Promoted: promoted}
+		if !f.Exported() && f.Pkg() != nil {
+			field.PkgPath = f.Pkg().Path()
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// kinds are the reflect kinds of basic types.
+var kinds = map[types.BasicKind]reflect.Kind{
+	types.Bool:          reflect.Bool,
+	types.Int:           reflect.Int,
+	types.Int8:          reflect.Int8,
+	types.Int16:         reflect.Int16,
+	types.Int32:         reflect.Int32,
+	types.Int64:         reflect.Int64,
+	types.Uint:          reflect.Uint,
+	types.Uint8:         reflect.Uint8,
+	types.Uint16:        reflect.Uint16,
+	types.Uint32:        reflect.Uint32,
+	types.Uint64:        reflect.Uint64,
+	types.Uintptr:       reflect.Uintptr,
+	types.Float32:       reflect.Float32,
+	types.Float64:       reflect.Float64,
+	types.Complex64:     reflect.Complex64,
+	types.Complex128:    reflect.Complex128,
+	types.String:        reflect.String,
+	types.UnsafePointer: reflect.UnsafePointer,
+}
+
+// kind returns the reflect kind of a type.
+func kind(typ types.Type) reflect.Kind {
+	switch underlying := typ.Underlying().(type) {
+	case *types.Basic:
+		return kinds[underlying.Kind()]
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Array:
+		return reflect.Array
+	case *types.Map:
+		return reflect.Map
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	}
+	return reflect.Invalid
+}
@@ -1,20 +1,22 @@
 package typescriptify
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
-	"path"
 	"reflect"
 	"strings"
-	"time"
+	"sync"
 
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
 	"github.com/fatih/structtag"
 	"github.com/tkrajina/go-reflector/reflector"
-	"golang.org/x/exp/slices"
 )
 
 const (
+	fileHeader          = "/* Do not change, this code is generated from Golang structs */\n\n"
 	tsDocTag            = "ts_doc"
 	tsTransformTag      = "ts_transform"
 	tsType              = "ts_type"
@@ -42,13 +44,53 @@ type TypeOptions struct {
 	TSType      string
 	TSDoc       string
 	TSTransform string
+	// ImportFrom is the module of TSType (for example `@acme/money`), the first identifier in TSType is
+	// imported from it when the type is used.
+	ImportFrom string
 }
 
 // FieldTags allow to add any tags to a field.
 type FieldTags map[string][]*structtag.Tag
 
-// Set tags to struct fields
+// taggedStructPackages are the packages of the structs the anonymous structs created by TryAddFieldTags and
+// TryTagAll are copied from (reflect.Type to package path), so that they are converted in the same file.
+var taggedStructPackages sync.Map
+
+// taggedStruct returns an anonymous struct with the fields sf, copied from the struct t.
+func taggedStruct(t reflect.Type, sf []reflect.StructField) reflect.Type {
+	result := reflect.StructOf(sf)
+	if pkgPath := structPackage(t); pkgPath != "" {
+		taggedStructPackages.Store(result, pkgPath)
+	}
+	return result
+}
+
+// structPackage returns the package path of a struct, or of the struct it is copied from if it was created
+// by TryAddFieldTags or TryTagAll.
+func structPackage(t reflect.Type) string {
+	if t.PkgPath() != "" {
+		return t.PkgPath()
+	}
+	if pkgPath, found := taggedStructPackages.Load(t); found {
+		return pkgPath.(string)
+	}
+	return ""
+}
+
+// Set tags to struct fields, invalid tags are logged (with the default slog logger) and ignored (see
+// TryAddFieldTags)
 func AddFieldTags(t reflect.Type, fieldTags *FieldTags) reflect.Type {
+	typ, err := TryAddFieldTags(t, fieldTags)
+	if err != nil {
+		slog.Error("Invalid tags ignored", slog.String("type", t.String()), slog.String("error", err.Error()))
+	}
+	return typ
+}
+
+// TryAddFieldTags returns an anonymous struct with tags added to its fields, and the errors (TagErrors) of
+// the fields with invalid tags, which are not changed.
+func TryAddFieldTags(t reflect.Type, fieldTags *FieldTags) (reflect.Type, error) {
+	var errs []error
 	sf := make([]reflect.StructField, 0)
 	for i := 0; i < t.NumField(); i++ {
 		sf = append(sf, t.Field(i))
@@ -58,49 +100,65 @@ func AddFieldTags(t reflect.Type, fieldTags *FieldTags) reflect.Type {
 			tagString := string(t.Field(i).Tag)
 			tags, err := structtag.Parse(tagString)
 			if err != nil {
-				fmt.Printf("Error parsing %q: %v\n", tagString, err)
+				errs = append(errs, &TagError{Type: t.String(), Field: t.Field(i).Name, Tag: tagString, Err: err})
 				continue
 			}
 			// set newTags
 			for _, tag := range newTags {
 				err := tags.Set(tag)
 				if err != nil {
-					fmt.Printf("Error setting tag %q: %v\n", tag, err)
+					errs = append(errs, &TagError{Type: t.String(), Field: t.Field(i).Name, Tag: tag.String(), Err: err})
 				}
 			}
 			sf[i].Tag = reflect.StructTag(tags.String())
 		}
 	}
-	return reflect.StructOf(sf)
+	return taggedStruct(t, sf), errors.Join(errs...)
 }
 
-// Create anonymous struct with provided new tags added to all fields
+// Create anonymous struct with provided new tags added to all fields, invalid tags are logged (with the
+// default slog logger) and ignored (see TryTagAll)
 func TagAll(t reflect.Type, newTags []string) reflect.Type {
+	typ, err := TryTagAll(t, newTags)
+	if err != nil {
+		slog.Error("Invalid tags ignored", slog.String("type", t.String()), slog.String("error", err.Error()))
+	}
+	return typ
+}
+
+// TryTagAll returns an anonymous struct with the options of all json tags replaced by newTags, and the
+// errors (TagErrors) of the fields with invalid tags, which are not changed.
+func TryTagAll(t reflect.Type, newTags []string) (reflect.Type, error) {
+	var errs []error
 	sf := make([]reflect.StructField, 0)
 	for i := 0; i < t.NumField(); i++ {
 		sf = append(sf, t.Field(i))
-
-		// parse field Tag
-		tagString := string(t.Field(i).Tag)
-		tags, err := structtag.Parse(tagString)
+		tag, err := tagWithJSONOptions(string(t.Field(i).Tag), newTags)
 		if err != nil {
-			fmt.Printf("Error parsing %q: %v\n", tagString, err)
-			continue
+			errs = append(errs, &TagError{Type: t.String(), Field: t.Field(i).Name, Tag: string(t.Field(i).Tag), Err: err})
 		}
-		// add newTags to json tag
-		jsonTag, err := tags.Get("json")
-		if err != nil {
-			fmt.Printf("Error getting json tag: %s\n", err)
-			continue
-		}
-		jsonTag.Options = newTags
-		err = tags.Set(jsonTag)
-		if err != nil {
-			fmt.Printf("Error setting %q tags: %s\n", newTags, err)
-		}
-		sf[i].Tag = reflect.StructTag(tags.String())
+		sf[i].Tag = reflect.StructTag(tag)
 	}
-	return reflect.StructOf(sf)
+	return taggedStruct(t, sf), errors.Join(errs...)
+}
+
+// tagWithJSONOptions returns a struct tag with the options of the json tag replaced (if there is one), or the
+// tag unchanged and an error if it is invalid.
+func tagWithJSONOptions(tagString string, newTags []string) (string, error) {
+	tags, err := structtag.Parse(tagString)
+	if err != nil {
+		return tagString, err
+	}
+	// add newTags to json tag
+	jsonTag, err := tags.Get("json")
+	if err != nil {
+		return tagString, nil // No json tag
+	}
+	jsonTag.Options = newTags
+	if err := tags.Set(jsonTag); err != nil {
+		return tagString, err
+	}
+	return tags.String(), nil
 }
 
 // StructType stores settings for transforming one Golang struct.
@@ -108,6 +166,7 @@ type StructType struct {
 	Type         reflect.Type
 	FieldOptions map[reflect.Type]TypeOptions
 	Name         string
+	Doc          string // TSDoc for the declaration, can be multi-line and contain tags like `@deprecated`
 }
 
 func NewStruct(i interface{}) *StructType {
@@ -130,8 +189,14 @@ func (st *StructType) WithFieldOpts(i interface{}, opts TypeOptions) *StructType
 	return st
 }
 
+func (st *StructType) WithDoc(doc string) *StructType {
+	st.Doc = doc
+	return st
+}
+
 type EnumType struct {
 	Type reflect.Type
+	Doc  string // TSDoc for the enum declaration
 }
 
 type enumElement struct {
@@ -146,22 +211,46 @@ type TypeScriptify struct {
 	CreateFromMethod  bool
 	CreateConstructor bool
 	BackupDir         string // If empty no backup
+	BackupKeep        int    // Number of backups kept per file (older ones are removed), 0 keeps all
 	DontExport        bool
 	CreateInterface   bool
+	Declaration       bool   // Only ambient declarations (for `.d.ts` files), without constructor bodies and custom code
+	JavaScript        bool   // Plain JavaScript with JSDoc types, interfaces are converted to `@typedef`s
+	Zod               bool   // Interfaces with zod schemas (checking the constraints of ValidateTags), see WithZod
+	InterfaceAndClass bool   // Create an interface and a class implementing it for every struct
+	InterfacePrefix   string // Prefix of interface names when InterfaceAndClass is set, "I" by default
 	ReadOnlyFields    bool
 	CamelCaseFields   bool
 	CamelCaseOptions  *CamelCaseOptions
+	ValidateTags      bool // Use go-playground/validator `validate` tags for required fields and constraint annotations
 	customImports     []string
+	errs              []error // Errors of AddEnum, returned by Model
 
-	structTypes []StructType
-	enumTypes   []EnumType
-	enums       map[reflect.Type][]enumElement
-	kinds       map[reflect.Kind]string
+	structTypes   []StructType
+	staticStructs []StaticStruct
+	staticEnums   []staticEnum
+	enumTypes     []EnumType
+	enums         map[reflect.Type][]enumElement
+	kinds         map[reflect.Kind]string
 
 	fieldTypeOptions map[reflect.Type]TypeOptions
+	typeNameOptions  map[string]TypeOptions
+
+	PackageFiles map[string]string // Go package path to file name (without extension) for ConvertToDir
+
+	Order DeclarationOrder // Order of declarations, OrderInsertion by default
+
+	Logger *slog.Logger // Conversion traces (debug level) and warnings, discarded if nil
+
+	Strict bool // Warnings (i.e. anonymous structs converted as UnknownStruct) are errors, see Diagnose
+
+	Emitter Emitter // Creates the code from the model, if nil TypeScript (or JavaScript) is created as set by the options
 
 	// throwaway, used when converting
-	alreadyConverted map[reflect.Type]bool
+	alreadyConverted map[any]bool // By GoType ID
+	added            []addedStruct
+	rootType         string // Go type of the analyzed struct added to the converter
+	diagnostics      Diagnostics
 }
 
 func New() *TypeScriptify {
@@ -194,43 +283,11 @@ func New() *TypeScriptify {
 	result.Indent = "    "
 	result.CreateFromMethod = false
 	result.CreateConstructor = true
+	result.InterfacePrefix = "I"
 
 	return result
 }
 
-func deepFields(typeOf reflect.Type) []reflect.StructField {
-	fields := make([]reflect.StructField, 0)
-
-	if typeOf.Kind() == reflect.Ptr {
-		typeOf = typeOf.Elem()
-	}
-
-	if typeOf.Kind() != reflect.Struct {
-		return fields
-	}
-
-	for i := 0; i < typeOf.NumField(); i++ {
-		f := typeOf.Field(i)
-
-		kind := f.Type.Kind()
-		if f.Anonymous && kind == reflect.Struct {
-			//fmt.Println(v.Interface())
-			fields = append(fields, deepFields(f.Type)...)
-		} else if f.Anonymous && kind == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct {
-			//fmt.Println(v.Interface())
-			fields = append(fields, deepFields(f.Type.Elem())...)
-		} else {
-			fields = append(fields, f)
-		}
-	}
-
-	return fields
-}
-
-func (ts TypeScriptify) logf(depth int, s string, args ...interface{}) {
-	fmt.Printf(strings.Repeat("   ", depth)+s+"\n", args...)
-}
-
 // ManageType can define custom options for fields of a specified type.
 //
 // This can be used instead of setting ts_type and ts_transform for all fields of a certain type.
@@ -249,6 +306,16 @@ func (t *TypeScriptify) ManageType(fld interface{}, opts TypeOptions) *TypeScrip
 	return t
 }
 
+// ManageTypeName is like ManageType, with the type given by its package path and name (i.e. `time.Time` or
+// `github.com/shopspring/decimal.Decimal`), so that the package doesn't need to be imported.
+func (t *TypeScriptify) ManageTypeName(typeName string, opts TypeOptions) *TypeScriptify {
+	if t.typeNameOptions == nil {
+		t.typeNameOptions = map[string]TypeOptions{}
+	}
+	t.typeNameOptions[typeName] = opts
+	return t
+}
+
 func (t *TypeScriptify) WithCreateFromMethod(b bool) *TypeScriptify {
 	t.CreateFromMethod = b
 	return t
@@ -259,6 +326,31 @@ func (t *TypeScriptify) WithInterface(b bool) *TypeScriptify {
 	return t
 }
 
+func (t *TypeScriptify) WithDeclaration(b bool) *TypeScriptify {
+	t.Declaration = b
+	return t
+}
+
+func (t *TypeScriptify) WithJavaScript(b bool) *TypeScriptify {
+	t.JavaScript = b
+	return t
+}
+
+// WithZod creates an interface and a zod schema (`UserSchema`, typed with the interface) for every struct, and
+// a schema for every enum. With ValidateTags the schemas check the constraints of the fields.
+func (t *TypeScriptify) WithZod(b bool) *TypeScriptify {
+	t.Zod = b
+	return t
+}
+
+// WithInterfaceAndClass creates an interface (`IUser`) and a class implementing it (`User`) for every struct.
+// Class fields referencing other structs are typed with their interfaces, and the constructor accepts the
+// interface (or a JSON string).
+func (t *TypeScriptify) WithInterfaceAndClass(b bool) *TypeScriptify {
+	t.InterfaceAndClass = b
+	return t
+}
+
 func (t *TypeScriptify) WithReadonlyFields(b bool) *TypeScriptify {
 	t.ReadOnlyFields = b
 	return t
@@ -270,6 +362,11 @@ func (t *TypeScriptify) WithCamelCaseFields(b bool, opts *CamelCaseOptions) *Typ
 	return t
 }
 
+func (t *TypeScriptify) WithValidateTags(b bool) *TypeScriptify {
+	t.ValidateTags = b
+	return t
+}
+
 func (t *TypeScriptify) WithConstructor(b bool) *TypeScriptify {
 	t.CreateConstructor = b
 	return t
@@ -285,6 +382,46 @@ func (t *TypeScriptify) WithBackupDir(b string) *TypeScriptify {
 	return t
 }
 
+// WithBackupKeep sets the number of backups kept per file, 0 keeps all.
+func (t *TypeScriptify) WithBackupKeep(n int) *TypeScriptify {
+	t.BackupKeep = n
+	return t
+}
+
+// WithOrder sets the order of declarations in the output, see DeclarationOrder.
+func (t *TypeScriptify) WithOrder(o DeclarationOrder) *TypeScriptify {
+	t.Order = o
+	return t
+}
+
+// WithStrict sets Strict, warnings found in the models are errors.
+func (t *TypeScriptify) WithStrict(b bool) *TypeScriptify {
+	t.Strict = b
+	return t
+}
+
+// WithLogger sets the logger of conversion traces and warnings.
+func (t *TypeScriptify) WithLogger(logger *slog.Logger) *TypeScriptify {
+	t.Logger = logger
+	return t
+}
+
+// WithEmitter sets the emitter creating the code from the model (see Model), instead of the default
+// TypeScript (or JavaScript) output. Custom code is not preserved by other emitters.
+func (t *TypeScriptify) WithEmitter(e Emitter) *TypeScriptify {
+	t.Emitter = e
+	return t
+}
+
+// WithPackageFile sets the file name (without extension) for declarations of types from a Go package, see ConvertToDir.
+func (t *TypeScriptify) WithPackageFile(pkgPath, fileName string) *TypeScriptify {
+	if t.PackageFiles == nil {
+		t.PackageFiles = map[string]string{}
+	}
+	t.PackageFiles[pkgPath] = fileName
+	return t
+}
+
 func (t *TypeScriptify) WithPrefix(p string) *TypeScriptify {
 	t.Prefix = p
 	return t
@@ -319,44 +456,36 @@ func (t *TypeScriptify) AddTypeWithName(typeOf reflect.Type, name string) *TypeS
 	return t
 }
 
-func (t *typeScriptClassBuilder) AddMapField(fieldName string, field reflect.StructField) {
-	keyType := field.Type.Key()
-	valueType := field.Type.Elem()
-	valueTypeName := valueType.Name()
-	if name, ok := t.types[valueType.Kind()]; ok {
-		valueTypeName = name
-	}
-	if valueType.Kind() == reflect.Array || valueType.Kind() == reflect.Slice {
-		valueTypeName = valueType.Elem().Name() + "[]"
-	}
-	if valueType.Kind() == reflect.Ptr {
-		valueTypeName = valueType.Elem().Name()
-	}
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-
-	keyTypeStr := keyType.Name()
-	// Key should always be string, no need for this:
-	// _, isSimple := t.types[keyType.Kind()]
-	// if !isSimple {
-	// 	keyTypeStr = t.prefix + keyType.Name() + t.suffix
-	// }
+// AddEnum adds an enum, invalid values are returned as an error by Model (and the conversion), see TryAddEnum.
+func (t *TypeScriptify) AddEnum(values interface{}) *TypeScriptify {
+	return t.AddEnumWithDoc(values, "")
+}
 
-	if valueType.Kind() == reflect.Struct {
-		t.fields = append(t.fields, fmt.Sprintf("%s%s: {[key: %s]: %s};", t.indent, fieldName, keyTypeStr, t.prefix+valueTypeName))
-		t.constructorBody = append(t.constructorBody, fmt.Sprintf("%s%sthis.%s = this.convertValues(source[\"%s\"], %s, true);", t.indent, t.indent, strippedFieldName, strippedFieldName, t.prefix+valueTypeName+t.suffix))
-	} else {
-		t.fields = append(t.fields, fmt.Sprintf("%s%s: {[key: %s]: %s};", t.indent, fieldName, keyTypeStr, valueTypeName))
-		t.constructorBody = append(t.constructorBody, fmt.Sprintf("%s%sthis.%s = source[\"%s\"];", t.indent, t.indent, strippedFieldName, strippedFieldName))
+// AddEnumWithDoc adds an enum (see `AddEnum()`) with a TSDoc comment for the enum declaration.
+func (t *TypeScriptify) AddEnumWithDoc(values interface{}, doc string) *TypeScriptify {
+	if err := t.TryAddEnumWithDoc(values, doc); err != nil {
+		t.errs = append(t.errs, err)
 	}
+	return t
 }
 
-func (t *TypeScriptify) AddEnum(values interface{}) *TypeScriptify {
-	if t.enums == nil {
-		t.enums = map[reflect.Type][]enumElement{}
+// TryAddEnum adds an enum (see `AddEnum()`), or returns an *EnumError if the values are invalid.
+func (t *TypeScriptify) TryAddEnum(values interface{}) error {
+	return t.TryAddEnumWithDoc(values, "")
+}
+
+// TryAddEnumWithDoc adds an enum with a TSDoc comment (see `AddEnumWithDoc()`), or returns an *EnumError if
+// the values are invalid.
+func (t *TypeScriptify) TryAddEnumWithDoc(values interface{}, doc string) error {
+	enumErr := func(format string, args ...interface{}) error {
+		return &EnumError{Type: fmt.Sprintf("%T", values), Err: fmt.Errorf(format, args...)}
 	}
 	items := reflect.ValueOf(values)
 	if items.Kind() != reflect.Slice {
-		panic(fmt.Sprintf("Values for %T isn't a slice", values))
+		return enumErr("not a slice")
+	}
+	if items.Len() == 0 {
+		return enumErr("no values")
 	}
 
 	var elements []enumElement
@@ -368,30 +497,36 @@ func (t *TypeScriptify) AddEnum(values interface{}) *TypeScriptify {
 			r := reflector.New(item.Interface())
 			val, err := r.Field("Value").Get()
 			if err != nil {
-				panic(fmt.Sprint("missing Type field in ", item.Type().String()))
+				return enumErr("missing Value field in %s", item.Type().String())
 			}
 			name, err := r.Field("TSName").Get()
 			if err != nil {
-				panic(fmt.Sprint("missing TSName field in ", item.Type().String()))
+				return enumErr("missing TSName field in %s", item.Type().String())
+			}
+			var is bool
+			if el.name, is = name.(string); !is {
+				return enumErr("TSName field of %s isn't a string", item.Type().String())
 			}
 			el.value = val
-			el.name = name.(string)
 		} else {
 			el.value = item.Interface()
 			if tsNamer, is := item.Interface().(TSNamer); is {
 				el.name = tsNamer.TSName()
 			} else {
-				panic(fmt.Sprint(item.Type().String(), " has no TSName method"))
+				return enumErr("%s has no TSName method", item.Type().String())
 			}
 		}
 
 		elements = append(elements, el)
 	}
+	if t.enums == nil {
+		t.enums = map[reflect.Type][]enumElement{}
+	}
 	ty := reflect.TypeOf(elements[0].value)
 	t.enums[ty] = elements
-	t.enumTypes = append(t.enumTypes, EnumType{Type: ty})
+	t.enumTypes = append(t.enumTypes, EnumType{Type: ty, Doc: doc})
 
-	return t
+	return nil
 }
 
 // AddEnumValues is deprecated, use `AddEnum()`
@@ -401,36 +536,73 @@ func (t *TypeScriptify) AddEnumValues(typeOf reflect.Type, values interface{}) *
 }
 
 func (t *TypeScriptify) Convert(customCode map[string]string) (string, error) {
+	result, err := t.ConvertDeclarations(customCode)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// ConvertDeclarations converts the models like Convert, and returns the code of every declaration.
+func (t *TypeScriptify) ConvertDeclarations(customCode map[string]string) (*ConvertResult, error) {
 	if t.CreateFromMethod {
 		fmt.Fprintln(os.Stderr, "FromMethod METHOD IS DEPRECATED AND WILL BE REMOVED!!!!!!")
 	}
+	if err := t.checkOptions(); err != nil {
+		return nil, err
+	}
 
-	t.alreadyConverted = make(map[reflect.Type]bool)
-	depth := 0
+	model, err := t.Model()
+	if err != nil {
+		return nil, err
+	}
+	return t.emit(model, customCode)
+}
 
-	result := ""
-	if len(t.customImports) > 0 {
-		// Put the custom imports, i.e.: `import Decimal from 'decimal.js'`
-		for _, cimport := range t.customImports {
-			result += cimport + "\n"
-		}
+// ConvertTo writes the converted models (see Convert) to w. Nothing is written if the conversion fails.
+func (t *TypeScriptify) ConvertTo(w io.Writer) (*ConvertResult, error) {
+	result, err := t.ConvertDeclarations(nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := result.WriteTo(w); err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	for _, enumTyp := range t.enumTypes {
-		elements := t.enums[enumTyp.Type]
-		typeScriptCode, err := t.convertEnum(depth, enumTyp.Type, elements)
-		if err != nil {
-			return "", err
-		}
-		result += "\n" + strings.Trim(typeScriptCode, " "+t.Indent+"\r\n")
+func (t *TypeScriptify) checkOptions() error {
+	if t.JavaScript && t.Declaration {
+		return fmt.Errorf("declarations can't be created with JavaScript output")
+	}
+	if t.InterfaceAndClass && (t.JavaScript || t.CreateInterface) {
+		return fmt.Errorf("interfaces and classes can't be created with JavaScript output or interfaces only")
 	}
+	if t.Zod && (t.JavaScript || t.Declaration || t.InterfaceAndClass) {
+		return fmt.Errorf("zod schemas can't be created with JavaScript output, declarations or interfaces and classes")
+	}
+	return nil
+}
 
-	for _, strctTyp := range t.structTypes {
-		typeScriptCode, err := t.convertType(depth, strctTyp.Type, customCode)
+// emit creates the code for all the declarations of a model.
+func (t *TypeScriptify) emit(model *ir.Model, customCode map[string]string) (*ConvertResult, error) {
+	emitter := t.emitter(customCode)
+	header, err := emitter.Header(model, typeImports(model.Declarations))
+	if err != nil {
+		return nil, err
+	}
+	result := &ConvertResult{Header: header}
+	for _, decl := range model.Declarations {
+		code, err := emitter.Declaration(model, decl)
 		if err != nil {
-			return "", err
+			return nil, err
+		}
+		result.Declarations = append(result.Declarations, ConvertedDeclaration{Name: decl.Name, GoType: decl.GoType, Kind: decl.Kind, Code: code})
+	}
+	if footerEmitter, is := emitter.(FooterEmitter); is {
+		if result.Footer, err = footerEmitter.Footer(model); err != nil {
+			return nil, err
 		}
-		result += "\n" + strings.Trim(typeScriptCode, " "+t.Indent+"\r\n")
 	}
 	return result, nil
 }
@@ -471,95 +643,35 @@ func loadCustomCode(fileName string) (map[string]string, error) {
 	return result, nil
 }
 
-func (t TypeScriptify) backup(fileName string) error {
-	fileIn, err := os.Open(fileName)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return err
-		}
-		// No neet to backup, just return:
-		return nil
-	}
-	defer fileIn.Close()
-
-	bytes, err := io.ReadAll(fileIn)
-	if err != nil {
-		return err
-	}
-
-	_, backupFn := path.Split(fmt.Sprintf("%s-%s.backup", fileName, time.Now().Format("2006-01-02T15_04_05.99")))
-	if t.BackupDir != "" {
-		backupFn = path.Join(t.BackupDir, backupFn)
-	}
-
-	return os.WriteFile(backupFn, bytes, os.FileMode(0700))
-}
-
 func (t TypeScriptify) ConvertToFile(fileName string) error {
-	if len(t.BackupDir) > 0 {
-		err := t.backup(fileName)
-		if err != nil {
-			return err
-		}
-	}
+	_, err := t.UpdateFile(fileName)
+	return err
+}
 
+// UpdateFile converts the models like ConvertToFile. The file is only written (and backed up) if its content
+// changes, and replaced atomically, so it is never left truncated if the conversion or writing fails.
+// Returns true if the file was written.
+func (t TypeScriptify) UpdateFile(fileName string) (bool, error) {
 	customCode, err := loadCustomCode(fileName)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	f, err := os.Create(fileName)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
 	converted, err := t.Convert(customCode)
 	if err != nil {
-		return err
-	}
-
-	if _, err := f.WriteString("/* Do not change, this code is generated from Golang structs */\n\n"); err != nil {
-		return err
-	}
-	if _, err := f.WriteString(converted); err != nil {
-		return err
-	}
-	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	return t.writeFile(fileName, converted)
 }
 
 type TSNamer interface {
 	TSName() string
 }
 
-func (t *TypeScriptify) convertEnum(depth int, typeOf reflect.Type, elements []enumElement) (string, error) {
-	t.logf(depth, "Converting enum %s", typeOf.String())
-	if _, found := t.alreadyConverted[typeOf]; found { // Already converted
-		return "", nil
-	}
-	t.alreadyConverted[typeOf] = true
-
-	entityName := t.Prefix + typeOf.Name() + t.Suffix
-	result := "enum " + entityName + " {\n"
-
-	for _, val := range elements {
-		result += fmt.Sprintf("%s%s = %#v,\n", t.Indent, val.name, val.value)
-	}
-
-	result += "}"
-
-	if !t.DontExport {
-		result = "export " + result
-	}
-
-	return result, nil
-}
-
-func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.StructField) TypeOptions {
+// getFieldOptions returns the options of a field of a struct set by tags, the struct field options, ManageTypeName
+// and ManageType. The field type is dereferenced if it's a pointer.
+func (t *TypeScriptify) getFieldOptions(structType GoType, field GoField, fieldType GoType) TypeOptions {
 	// By default use options defined by tags:
 	opts := TypeOptions{
 		TSTransform: field.Tag.Get(tsTransformTag),
@@ -570,21 +682,31 @@ func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.S
 	overrides := []TypeOptions{}
 
 	// But there is maybe an struct-specific override:
-	for _, strct := range t.structTypes {
-		if strct.FieldOptions == nil {
+	for _, strct := range t.added {
+		if strct.typ.ID() != structType.ID() {
 			continue
 		}
-		if strct.Type == structType {
-			if fldOpts, found := strct.FieldOptions[field.Type]; found {
-				overrides = append(overrides, fldOpts)
-			}
+		if fldOpts, found := strct.fieldOptions(fieldType); found {
+			overrides = append(overrides, fldOpts)
 		}
 	}
 
-	if fldOpts, found := t.fieldTypeOptions[field.Type]; found {
-		overrides = append(overrides, fldOpts)
+	if fieldType.Name() != "" {
+		if fldOpts, found := t.typeNameOptions[fieldType.PkgPath()+"."+fieldType.Name()]; found {
+			overrides = append(overrides, fldOpts)
+		}
+	}
+	for typ, fldOpts := range t.fieldTypeOptions {
+		if sameType(fieldType, reflectType{typ}) {
+			overrides = append(overrides, fldOpts)
+		}
 	}
 
+	return mergeTypeOptions(opts, overrides)
+}
+
+// mergeTypeOptions returns the options overridden by the options set in overrides (the last one wins).
+func mergeTypeOptions(opts TypeOptions, overrides []TypeOptions) TypeOptions {
 	for _, o := range overrides {
 		if o.TSTransform != "" {
 			opts.TSTransform = o.TSTransform
@@ -592,6 +714,12 @@ func (t *TypeScriptify) getFieldOptions(structType reflect.Type, field reflect.S
 		if o.TSType != "" {
 			opts.TSType = o.TSType
 		}
+		if o.TSDoc != "" {
+			opts.TSDoc = o.TSDoc
+		}
+		if o.ImportFrom != "" {
+			opts.ImportFrom = o.ImportFrom
+		}
 	}
 
 	return opts
@@ -636,286 +764,301 @@ func (t *TypeScriptify) getJSONFieldName(field reflect.StructField, isPtr bool)
 	return jsonFieldName
 }
 
-func (t *TypeScriptify) convertType(depth int, typeOf reflect.Type, customCode map[string]string) (string, error) {
-	if _, found := t.alreadyConverted[typeOf]; found { // Already converted
-		return "", nil
+// Model analyzes the added enums and structs (and the structs used in their fields). Declarations are
+// ordered as set with WithOrder.
+func (t *TypeScriptify) Model() (*ir.Model, error) {
+	model, diagnostics, err := t.analyze()
+	if err != nil {
+		return nil, err
+	}
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
 	}
-	t.logf(depth, "Converting type %s", typeOf.String())
+	for _, diagnostic := range diagnostics {
+		t.logDiagnostic(diagnostic)
+	}
+	return model, nil
+}
 
-	t.alreadyConverted[typeOf] = true
+// analyze returns the model, and all the problems found in the structs (see Diagnose).
+func (t *TypeScriptify) analyze() (*ir.Model, Diagnostics, error) {
+	t.alreadyConverted = make(map[any]bool)
+	t.diagnostics = nil
+	depth := 0
+	if len(t.errs) > 0 {
+		return nil, nil, errors.Join(t.errs...)
+	}
 
-	typeName := typeOf.Name()
-	if typeName == "" {
-		idx := slices.IndexFunc(t.structTypes,
-			func(structType StructType) bool {
-				return typeOf == structType.Type
-			})
-		if idx >= 0 && t.structTypes[idx].Name != "" {
-			typeName = t.structTypes[idx].Name
-		} else {
-			fmt.Println("Use .AddTypeWithName to avoid UnknownStruct")
-			typeName = "UnknownStruct"
+	model := new(ir.Model)
+	for _, enumTyp := range t.enumTypes {
+		var members []*ir.EnumMember
+		for _, el := range t.enums[enumTyp.Type] {
+			members = append(members, &ir.EnumMember{Name: el.name, Value: enumValue(el.value)})
+		}
+		if decl := t.analyzeEnum(depth, reflectType{enumTyp.Type}, members, enumTyp.Doc); decl != nil {
+			model.Declarations = append(model.Declarations, decl)
 		}
 	}
-	entityName := t.Prefix + typeName + t.Suffix
-	result := ""
-	if t.CreateInterface {
-		result += fmt.Sprintf("interface %s {\n", entityName)
-	} else {
-		result += fmt.Sprintf("class %s {\n", entityName)
+	for _, enum := range t.staticEnums {
+		if decl := t.analyzeEnum(depth, enum.typ, enum.members, ""); decl != nil {
+			model.Declarations = append(model.Declarations, decl)
+		}
+	}
+	t.added = t.addedStructs()
+	for _, strct := range t.added {
+		t.rootType = strct.typ.String()
+		decls, err := t.analyzeStruct(depth, strct.typ, strct.jsonOptions, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		model.Declarations = append(model.Declarations, decls...)
+	}
+	t.checkNames(model.Declarations)
+
+	var err error
+	model.Declarations, err = orderDeclarations(model.Declarations, t.Order)
+	if err != nil {
+		return nil, nil, err
+	}
+	return model, t.diagnostics, nil
+}
+
+func (t *TypeScriptify) analyzeEnum(depth int, typ GoType, members []*ir.EnumMember, doc string) *ir.Declaration {
+	t.logType(depth, "Converting enum", typ.String())
+	if _, found := t.alreadyConverted[typ.ID()]; found { // Already converted
+		return nil
+	}
+	t.alreadyConverted[typ.ID()] = true
+
+	return &ir.Declaration{
+		Name:    t.Prefix + typ.Name() + t.Suffix,
+		GoType:  typ.String(),
+		Package: typ.PkgPath(),
+		Kind:    ir.KindEnum,
+		Doc:     doc,
+		Members: members,
+	}
+}
+
+// enumValue converts an enum value to a string or number.
+func enumValue(value interface{}) interface{} {
+	val := reflect.ValueOf(value)
+	switch {
+	case val.Kind() == reflect.String:
+		return val.String()
+	case val.CanInt():
+		return val.Int()
+	case val.CanUint():
+		return val.Uint()
+	case val.CanFloat():
+		return val.Float()
+	}
+	return value
+}
+
+// analyzeStruct returns the declaration of a struct, after the declarations of the structs it uses (which
+// are not already converted). Path is the path of the struct from the added struct (for diagnostics), empty
+// for the added struct. JSONOptions, if set, replace the options of the json tags of the fields (like TagAll).
+func (t *TypeScriptify) analyzeStruct(depth int, typeOf GoType, jsonOptions []string, path string) ([]*ir.Declaration, error) {
+	if _, found := t.alreadyConverted[typeOf.ID()]; found { // Already converted
+		return nil, nil
+	}
+	t.logType(depth, "Converting type", typeOf.String())
+
+	t.alreadyConverted[typeOf.ID()] = true
+
+	decl := &ir.Declaration{
+		Name:    t.structName(typeOf),
+		GoType:  typeOf.String(),
+		Package: typeOf.PkgPath(),
+		Kind:    ir.KindStruct,
 	}
-	if !t.DontExport {
-		result = "export " + result
+	added := t.addedStruct(typeOf)
+	if added != nil {
+		decl.Doc = added.doc
 	}
-	builder := typeScriptClassBuilder{
-		types:          t.kinds,
-		indent:         t.Indent,
-		prefix:         t.Prefix,
-		suffix:         t.Suffix,
-		readOnlyFields: t.ReadOnlyFields,
+	if typeOf.Name() == "" && (added == nil || added.name == "") {
+		t.report(SeverityWarning, path, ErrUnnamedStruct)
+	} else if typeOf.Generic() {
+		t.report(SeverityError, path, fmt.Errorf("%s: %w", typeOf, ErrGenericStruct))
 	}
 
-	fields := deepFields(typeOf)
+	var dependencies []*ir.Declaration
+	var elemPath string // Path of the structs used in the analyzed field
+	convert := func(typ GoType) (string, error) {
+		decls, err := t.analyzeStruct(depth+1, typ, nil, elemPath)
+		if err != nil {
+			return "", err
+		}
+		dependencies = append(decls, dependencies...)
+		return t.structName(typ), nil
+	}
+
+	fields, err := typeOf.Fields()
+	if err != nil {
+		return nil, err
+	}
 	for _, field := range fields {
-		isPtr := field.Type.Kind() == reflect.Ptr
+		if len(jsonOptions) > 0 && !field.Promoted {
+			tag, err := tagWithJSONOptions(string(field.Tag), jsonOptions)
+			if err != nil {
+				return nil, &TagError{Type: typeOf.String(), Field: field.Name, Tag: string(field.Tag), Err: err}
+			}
+			field.Tag = reflect.StructTag(tag)
+		}
+		fieldType := field.Type
+		isPtr := fieldType.Kind() == reflect.Ptr
 		if isPtr {
-			field.Type = field.Type.Elem()
+			fieldType = fieldType.Elem()
 		}
-		jsonFieldName := t.getJSONFieldName(field, isPtr)
+		jsonFieldName := t.getJSONFieldName(reflect.StructField{Name: field.Name, PkgPath: field.PkgPath, Tag: field.Tag}, isPtr)
 		if len(jsonFieldName) == 0 || jsonFieldName == "-" {
 			continue
 		}
 
-		var err error
-		fldOpts := t.getFieldOptions(typeOf, field)
-		if fldOpts.TSDoc != "" {
-			builder.addFieldDefinitionLine("/** " + fldOpts.TSDoc + " */")
-		}
-		if fldOpts.TSTransform != "" {
-			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
-			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
-		} else if _, isEnum := t.enums[field.Type]; isEnum {
-			t.logf(depth, "- enum field %s.%s", typeOf.Name(), field.Name)
-			builder.AddEnumField(jsonFieldName, field)
-		} else if fldOpts.TSType != "" { // Struct:
-			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
-			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
-		} else if field.Type.Kind() == reflect.Struct { // Struct:
-			t.logf(depth, "- struct %s.%s (%s)", typeOf.Name(), field.Name, field.Type.String())
-			typeScriptChunk, err := t.convertType(depth+1, field.Type, customCode)
+		fieldPath := joinPath(path, field.Name)
+		elemPath = fieldPath + fieldElemPath(fieldType)
+		fldOpts := t.getFieldOptions(typeOf, field, fieldType)
+		fld := &ir.Field{
+			Name:      strings.TrimSuffix(jsonFieldName, "?"),
+			GoName:    field.Name,
+			Optional:  strings.HasSuffix(jsonFieldName, "?"),
+			Doc:       fldOpts.TSDoc,
+			Transform: fldOpts.TSTransform,
+		}
+		if t.ValidateTags {
+			fldConstraints, err := parseValidateTag(field.Tag.Get(validateTag), fieldType.Kind())
 			if err != nil {
-				return "", err
-			}
-			if typeScriptChunk != "" {
-				result = typeScriptChunk + "\n" + result
-			}
-			builder.AddStructField(jsonFieldName, field)
-		} else if field.Type.Kind() == reflect.Map {
-			t.logf(depth, "- map field %s.%s", typeOf.Name(), field.Name)
-			// Also convert map key types if needed
-			var keyTypeToConvert reflect.Type
-			switch field.Type.Key().Kind() {
-			case reflect.Struct:
-				keyTypeToConvert = field.Type.Key()
-			case reflect.Ptr:
-				keyTypeToConvert = field.Type.Key().Elem()
-			}
-			if keyTypeToConvert != nil {
-				typeScriptChunk, err := t.convertType(depth+1, keyTypeToConvert, customCode)
-				if err != nil {
-					return "", err
-				}
-				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
-				}
+				t.report(SeverityError, fieldPath, err)
+				continue
 			}
-			// Also convert map value types if needed
-			var valueTypeToConvert reflect.Type
-			switch field.Type.Elem().Kind() {
-			case reflect.Struct:
-				valueTypeToConvert = field.Type.Elem()
-			case reflect.Ptr:
-				valueTypeToConvert = field.Type.Elem().Elem()
+			if fldConstraints.required {
+				fld.Optional = false
 			}
-			if valueTypeToConvert != nil {
-				typeScriptChunk, err := t.convertType(depth+1, valueTypeToConvert, customCode)
-				if err != nil {
-					return "", err
-				}
-				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
-				}
+			if !fldConstraints.IsZero() {
+				fld.Constraints = &fldConstraints.Constraints
 			}
+		}
 
-			builder.AddMapField(jsonFieldName, field)
-		} else if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Array { // Slice:
-			if field.Type.Elem().Kind() == reflect.Ptr { //extract ptr type
-				field.Type = field.Type.Elem()
-			}
+		if kind := fieldType.Kind(); (kind == reflect.Chan || kind == reflect.Func) && fldOpts.TSType == "" && fldOpts.TSTransform == "" {
+			t.report(SeverityWarning, fieldPath, fmt.Errorf("%w: %s", ErrIgnoredField, kind))
+			continue
+		}
 
-			arrayDepth := 1
-			for field.Type.Elem().Kind() == reflect.Slice { // Slice of slices:
-				field.Type = field.Type.Elem()
-				arrayDepth++
-			}
+		var err error
+		fld.Type, err = t.fieldType(depth, typeOf, field.Name, fieldType, fldOpts, convert)
+		if err != nil {
+			return nil, err
+		}
+		if fld.Type == nil {
+			t.report(SeverityError, fieldPath, fmt.Errorf("%w for %s (%s/%s)", ErrUnsupportedType, fieldType.Kind().String(), jsonFieldName, fieldType.Name()))
+			continue
+		}
+		decl.Fields = append(decl.Fields, fld)
+	}
 
-			if field.Type.Elem().Kind() == reflect.Struct { // Slice of structs:
-				t.logf(depth, "- struct slice %s.%s (%s)", typeOf.Name(), field.Name, field.Type.String())
-				typeScriptChunk, err := t.convertType(depth+1, field.Type.Elem(), customCode)
-				if err != nil {
-					return "", err
-				}
-				if typeScriptChunk != "" {
-					result = typeScriptChunk + "\n" + result
-				}
-				builder.AddArrayOfStructsField(jsonFieldName, field, arrayDepth)
-			} else { // Slice of simple fields:
-				t.logf(depth, "- slice field %s.%s", typeOf.Name(), field.Name)
-				err = builder.AddSimpleArrayField(jsonFieldName, field, arrayDepth, fldOpts)
-			}
-		} else { // Simple field:
-			t.logf(depth, "- simple field %s.%s", typeOf.Name(), field.Name)
-			err = builder.AddSimpleField(jsonFieldName, field, fldOpts)
+	return append(dependencies, decl), nil
+}
+
+// fieldType returns the type of a field (nil if it can't be converted). Structs used in the field are
+// converted with convert, which returns their declaration names.
+func (t *TypeScriptify) fieldType(depth int, typeOf GoType, fieldName string, fieldType GoType, opts TypeOptions, convert func(GoType) (string, error)) (*ir.TypeExpr, error) {
+	isEnum := t.isEnum(fieldType)
+	switch {
+	case opts.TSTransform != "" || (opts.TSType != "" && !isEnum):
+		t.logField(depth, "simple", typeOf.Name(), fieldName, "")
+		if opts.TSType != "" {
+			return ir.Custom(opts.TSType, opts.ImportFrom), nil
 		}
-		if err != nil {
-			return "", err
+		if name, found := t.kinds[fieldType.Kind()]; found {
+			return ir.Primitive(name), nil
 		}
+		return nil, nil
+	case isEnum:
+		t.logField(depth, "enum", typeOf.Name(), fieldName, "")
+		return ir.Enum(t.Prefix + fieldType.Name() + t.Suffix), nil
+	case fieldType.Kind() == reflect.Struct:
+		t.logField(depth, "struct", typeOf.Name(), fieldName, fieldType.String())
+	case fieldType.Kind() == reflect.Map:
+		t.logField(depth, "map", typeOf.Name(), fieldName, "")
+	case fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array:
+		t.logField(depth, "slice", typeOf.Name(), fieldName, fieldType.String())
+	default:
+		t.logField(depth, "simple", typeOf.Name(), fieldName, "")
 	}
+	return t.typeExpr(fieldType, convert)
+}
 
-	if t.CreateFromMethod {
-		t.CreateConstructor = true
+// typeExpr returns the type for a Go type (nil if it can't be converted).
+func (t *TypeScriptify) typeExpr(typ GoType, convert func(GoType) (string, error)) (*ir.TypeExpr, error) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if name, found := t.kinds[typ.Kind()]; found {
+		return ir.Primitive(name), nil
 	}
 
-	result += strings.Join(builder.fields, "\n") + "\n"
-	if !t.CreateInterface {
-		constructorBody := strings.Join(builder.constructorBody, "\n")
-		needsConvertValue := strings.Contains(constructorBody, "this.convertValues")
-		if t.CreateFromMethod {
-			result += fmt.Sprintf("\n%sstatic createFrom(source: any = {}) {\n", t.Indent)
-			result += fmt.Sprintf("%s%sreturn new %s(source);\n", t.Indent, t.Indent, entityName)
-			result += fmt.Sprintf("%s}\n", t.Indent)
+	switch typ.Kind() {
+	case reflect.Struct:
+		name, err := convert(typ)
+		if err != nil {
+			return nil, err
 		}
-		if t.CreateConstructor {
-			result += fmt.Sprintf("\n%sconstructor(source: any = {}) {\n", t.Indent)
-			result += t.Indent + t.Indent + "if ('string' === typeof source) source = JSON.parse(source);\n"
-			result += constructorBody + "\n"
-			result += fmt.Sprintf("%s}\n", t.Indent)
+		return ir.Struct(name), nil
+	case reflect.Slice, reflect.Array:
+		elem, err := t.typeExpr(typ.Elem(), convert)
+		if elem == nil || err != nil {
+			return nil, err
 		}
-		if needsConvertValue && (t.CreateConstructor || t.CreateFromMethod) {
-			result += "\n" + indentLines(strings.ReplaceAll(tsConvertValuesFunc, "\t", t.Indent), 1) + "\n"
+		return ir.Array(elem), nil
+	case reflect.Map:
+		key, err := t.typeExpr(typ.Key(), convert)
+		if key == nil || err != nil {
+			return nil, err
 		}
-	}
-
-	if customCode != nil {
-		code := customCode[entityName]
-		if len(code) != 0 {
-			result += t.Indent + "//[" + entityName + ":]\n" + code + "\n\n" + t.Indent + "//[end]\n"
+		elem, err := t.typeExpr(typ.Elem(), convert)
+		if elem == nil || err != nil {
+			return nil, err
 		}
+		return ir.Map(key, elem), nil
 	}
-
-	result += "}"
-
-	return result, nil
+	return nil, nil
 }
 
-func (t *TypeScriptify) AddImport(i string) {
-	for _, cimport := range t.customImports {
-		if cimport == i {
-			return
+// isEnum returns true if a type is an enum added with AddEnum or AddStaticEnum.
+func (t *TypeScriptify) isEnum(typ GoType) bool {
+	for enumType := range t.enums {
+		if sameType(typ, reflectType{enumType}) {
+			return true
 		}
 	}
-
-	t.customImports = append(t.customImports, i)
-}
-
-type typeScriptClassBuilder struct {
-	types                map[reflect.Kind]string
-	indent               string
-	fields               []string
-	createFromMethodBody []string
-	constructorBody      []string
-	prefix, suffix       string
-	readOnlyFields       bool
-}
-
-func (t *typeScriptClassBuilder) AddSimpleArrayField(fieldName string, field reflect.StructField, arrayDepth int, opts TypeOptions) error {
-	fieldType, kind := field.Type.Elem().Name(), field.Type.Elem().Kind()
-	typeScriptType := t.types[kind]
-
-	if len(fieldName) > 0 {
-		strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-		if len(opts.TSType) > 0 {
-			t.addField(fieldName, opts.TSType)
-			t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
-			return nil
-		} else if len(typeScriptType) > 0 {
-			t.addField(fieldName, fmt.Sprint(typeScriptType, strings.Repeat("[]", arrayDepth)))
-			t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
-			return nil
+	for _, enum := range t.staticEnums {
+		if sameType(typ, enum.typ) {
+			return true
 		}
 	}
-
-	return fmt.Errorf("cannot find type for %s (%s/%s)", kind.String(), fieldName, fieldType)
+	return false
 }
 
-func (t *typeScriptClassBuilder) AddSimpleField(fieldName string, field reflect.StructField, opts TypeOptions) error {
-	fieldType, kind := field.Type.Name(), field.Type.Kind()
-
-	typeScriptType := t.types[kind]
-	if len(opts.TSType) > 0 {
-		typeScriptType = opts.TSType
-	}
-
-	if len(typeScriptType) > 0 && len(fieldName) > 0 {
-		strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-		t.addField(fieldName, typeScriptType)
-		if opts.TSTransform == "" {
-			t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
+// structName returns the declaration name of a struct.
+func (t *TypeScriptify) structName(typeOf GoType) string {
+	typeName := typeOf.Name()
+	if typeName == "" {
+		if added := t.addedStruct(typeOf); added != nil && added.name != "" {
+			typeName = added.name
 		} else {
-			val := fmt.Sprintf(`source["%s"]`, strippedFieldName)
-			expression := strings.Replace(opts.TSTransform, "__VALUE__", val, -1)
-			t.addInitializerFieldLine(strippedFieldName, expression)
+			typeName = "UnknownStruct"
 		}
-		return nil
 	}
-
-	return fmt.Errorf("cannot find type for %s (%s/%s)", kind.String(), fieldName, fieldType)
-}
-
-func (t *typeScriptClassBuilder) AddEnumField(fieldName string, field reflect.StructField) {
-	fieldType := field.Type.Name()
-	t.addField(fieldName, t.prefix+fieldType+t.suffix)
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("source[\"%s\"]", strippedFieldName))
-}
-
-func (t *typeScriptClassBuilder) AddStructField(fieldName string, field reflect.StructField) {
-	fieldType := field.Type.Name()
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-	t.addField(fieldName, t.prefix+fieldType+t.suffix)
-	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", strippedFieldName, t.prefix+fieldType+t.suffix))
-}
-
-func (t *typeScriptClassBuilder) AddArrayOfStructsField(fieldName string, field reflect.StructField, arrayDepth int) {
-	fieldType := field.Type.Elem().Name()
-	strippedFieldName := strings.ReplaceAll(fieldName, "?", "")
-	t.addField(fieldName, fmt.Sprint(t.prefix+fieldType+t.suffix, strings.Repeat("[]", arrayDepth)))
-	t.addInitializerFieldLine(strippedFieldName, fmt.Sprintf("this.convertValues(source[\"%s\"], %s)", strippedFieldName, t.prefix+fieldType+t.suffix))
-}
-
-func (t *typeScriptClassBuilder) addInitializerFieldLine(fld, initializer string) {
-	t.createFromMethodBody = append(t.createFromMethodBody, fmt.Sprint(t.indent, t.indent, "result.", fld, " = ", initializer, ";"))
-	t.constructorBody = append(t.constructorBody, fmt.Sprint(t.indent, t.indent, "this.", fld, " = ", initializer, ";"))
+	return t.Prefix + typeName + t.Suffix
 }
 
-func (t *typeScriptClassBuilder) addFieldDefinitionLine(line string) {
-	t.fields = append(t.fields, t.indent+line)
-}
-
-func (t *typeScriptClassBuilder) addField(fld, fldType string) {
-	ro := ""
-	if t.readOnlyFields {
-		ro = "readonly "
+func (t *TypeScriptify) AddImport(i string) {
+	for _, cimport := range t.customImports {
+		if cimport == i {
+			return
+		}
 	}
-	t.fields = append(t.fields, fmt.Sprint(t.indent, ro, fld, ": ", fldType, ";"))
+
+	t.customImports = append(t.customImports, i)
 }
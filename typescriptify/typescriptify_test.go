@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
 	"github.com/fatih/structtag"
 	"github.com/stretchr/testify/assert"
 )
@@ -1127,3 +1128,285 @@ func TestTypescriptifyCamelCasePreserveConsecutiveUppercase(t *testing.T) {
 }`
 	testConverter(t, converter, false, desiredResult, nil)
 }
+
+func TestTypescriptifyDeclarationDoc(t *testing.T) {
+	t.Parallel()
+	type Person struct {
+		Name string `json:"name" ts_doc:"Name comment\n@since 1.2"`
+		Age  int    `json:"age" ts_doc:"Not a */ comment end"`
+	}
+
+	converter := New().
+		Add(NewStruct(Person{}).WithDoc("A person\n@deprecated use Human")).
+		AddEnumWithDoc(allGenders, "Gender of a person").
+		WithConstructor(false).
+		WithBackupDir("")
+
+	desiredResult := `/** Gender of a person */
+export enum Gender {
+	MALE = "m",
+	FEMALE = "f",
+}
+/**
+ * A person
+ * @deprecated use Human
+ */
+export class Person {
+	/**
+	 * Name comment
+	 * @since 1.2
+	 */
+	name: string;
+	/** Not a *\/ comment end */
+	age: number;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestValidateTags(t *testing.T) {
+	t.Parallel()
+	type SignUp struct {
+		Email    string   `json:"email,omitempty" validate:"required,email"`
+		Name     *string  `json:"name" validate:"required,min=3,max=64" ts_doc:"Display name"`
+		Age      int      `json:"age,omitempty" validate:"gte=18"`
+		Tags     []string `json:"tags" validate:"max=5,dive,alpha"`
+		Color    string   `json:"color" validate:"oneof=red green"`
+		Referrer string   `json:"referrer,omitempty"`
+	}
+
+	converter := New().
+		Add(SignUp{}).
+		WithValidateTags(true).
+		WithInterface(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface SignUp {
+	/** @format email */
+	email: string;
+	/**
+	 * Display name
+	 * @minLength 3
+	 * @maxLength 64
+	 */
+	name: string;
+	/** @minimum 18 */
+	age?: number;
+	/** @maxItems 5 */
+	tags: string[];
+	/** @oneOf "red" | "green" */
+	color: string;
+	referrer?: string;
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestDeclaration(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddType(reflect.TypeOf(Holliday{})).
+		AddEnum(allWeekdaysV1).
+		WithDeclaration(true).
+		WithIndent("\t").
+		WithBackupDir("")
+
+	desiredResult := `export declare enum Weekday {
+	SUNDAY = 0,
+	MONDAY = 1,
+	TUESDAY = 2,
+	WEDNESDAY = 3,
+	THURSDAY = 4,
+	FRIDAY = 5,
+	SATURDAY = 6,
+}
+export declare class Holliday {
+	name: string;
+	weekday: Weekday;
+
+	constructor(source?: any);
+}`
+	typeScriptCode, err := converter.Convert(map[string]string{"Holliday": "\tcustom(): void {}"})
+	assert.Nil(t, err)
+	assert.Equal(t, desiredResult, strings.TrimSpace(typeScriptCode))
+}
+
+func TestDeclarationWithReferences(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddType(reflect.TypeOf(Person{})).
+		WithDeclaration(true).
+		WithBackupDir("")
+
+	desiredResult := `export declare class Dummy {
+	something: string;
+
+	constructor(source?: any);
+}
+export declare class Address {
+	duration: number;
+	text?: string;
+	Text2?: string;
+
+	constructor(source?: any);
+}
+export declare class Person {
+	name: string;
+	nicknames: string[];
+	addresses: Address[];
+	address?: Address;
+	metadata: {[key:string]:string};
+	friends: Person[];
+	a: Dummy;
+
+	constructor(source?: any);
+}`
+	testConverter(t, converter, true, desiredResult, nil)
+}
+
+func TestInterfaceAndClass(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		AddType(reflect.TypeOf(Person{})).
+		WithInterfaceAndClass(true).
+		WithBackupDir("")
+
+	desiredResult := `export interface IDummy {
+	something: string;
+}
+export class Dummy implements IDummy {
+	something: string;
+
+	constructor(input: IDummy | string = {} as IDummy) {
+		const source: any = 'string' === typeof input ? JSON.parse(input) : input;
+		this.something = source["something"];
+	}
+}
+export interface IAddress {
+	duration: number;
+	text?: string;
+	Text2?: string;
+}
+export class Address implements IAddress {
+	duration: number;
+	text?: string;
+	Text2?: string;
+
+	constructor(input: IAddress | string = {} as IAddress) {
+		const source: any = 'string' === typeof input ? JSON.parse(input) : input;
+		this.duration = source["duration"];
+		this.text = source["text"];
+		this.Text2 = source["Text2"];
+	}
+}
+export interface IPerson {
+	name: string;
+	nicknames: string[];
+	addresses: IAddress[];
+	address?: IAddress;
+	metadata: {[key:string]:string};
+	friends: IPerson[];
+	a: IDummy;
+}
+export class Person implements IPerson {
+	name: string;
+	nicknames: string[];
+	addresses: IAddress[];
+	address?: IAddress;
+	metadata: {[key:string]:string};
+	friends: IPerson[];
+	a: IDummy;
+
+	constructor(input: IPerson | string = {} as IPerson) {
+		const source: any = 'string' === typeof input ? JSON.parse(input) : input;
+		this.name = source["name"];
+		this.nicknames = source["nicknames"];
+		this.addresses = this.convertValues(source["addresses"], Address);
+		this.address = this.convertValues(source["address"], Address);
+		this.metadata = JSON.parse(source["metadata"] || "{}");
+		this.friends = this.convertValues(source["friends"], Person);
+		this.a = this.convertValues(source["a"], Dummy);
+	}
+
+	` + tsConvertValuesFunc + `
+}`
+	jsn := jsonizeOrPanic(Person{
+		Address:   &Address{Text1: "txt1"},
+		Addresses: []Address{{Text1: "111"}},
+		Metadata:  `{"something": "aaa"}`,
+	})
+	testConverter(t, converter, true, desiredResult, []string{
+		`new Person()`,
+		`new Person(` + jsn + ` as any)?.address instanceof Address`,
+		`(new Person(` + jsn + ` as any)?.addresses[0] as Address)?.text === "111"`,
+		`new Person(` + jsonizeOrPanic(jsn) + `).metadata?.something === "aaa"`,
+	})
+}
+
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type Price struct {
+	Net   Money     `json:"net"`
+	Gross *Money    `json:"gross"`
+	Time  time.Time `json:"time"`
+}
+
+func TestManagedTypeImports(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Price{}).
+		ManageType(Money{}, TypeOptions{TSType: "Money", TSTransform: "Money.from(__VALUE__)", ImportFrom: "@acme/money"}).
+		ManageType(time.Time{}, TypeOptions{TSType: "Record<Zone, DateTime> | null", ImportFrom: "@acme/time"}).
+		ManageType(Dummy{}, TypeOptions{TSType: "Unused", ImportFrom: "@acme/unused"}).
+		WithConstructor(false).
+		WithIndent("\t").
+		WithBackupDir("")
+	converter.AddImport("import { Other } from \"@acme/money\";")
+
+	desiredResult := `import { Other } from "@acme/money";
+import { Money } from "@acme/money";
+import { DateTime, Zone } from "@acme/time";
+
+export class Price {
+	net: Money;
+	gross?: Money;
+	time: Record<Zone, DateTime> | null;
+}`
+	typeScriptCode, err := converter.Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, desiredResult, strings.TrimSpace(typeScriptCode))
+}
+
+func TestModel(t *testing.T) {
+	t.Parallel()
+	converter := New().
+		Add(Holliday{}).
+		Add(WithMap{}).
+		AddEnum(allWeekdaysV1).
+		WithBackupDir("")
+
+	model, err := converter.Model()
+	assert.Nil(t, err)
+
+	var names []string
+	for _, decl := range model.Declarations {
+		names = append(names, decl.Name)
+	}
+	assert.Equal(t, []string{"Weekday", "Holliday", "Address", "WithMap"}, names)
+
+	weekday := model.Declaration("Weekday")
+	assert.Equal(t, ir.KindEnum, weekday.Kind)
+	assert.Equal(t, "typescriptify.Weekday", weekday.GoType)
+	assert.Equal(t, "github.com/GoodNotes/typescriptify-golang-structs/typescriptify", weekday.Package)
+	assert.Equal(t, &ir.EnumMember{Name: "MONDAY", Value: int64(1)}, weekday.Members[1])
+
+	assert.Equal(t, []*ir.Field{
+		{Name: "name", GoName: "Name", Type: ir.Primitive("string")},
+		{Name: "weekday", GoName: "Weekday", Type: ir.Enum("Weekday")},
+	}, model.Declaration("Holliday").Fields)
+
+	withMap := model.Declaration("WithMap")
+	assert.Equal(t, &ir.Field{Name: "ptrMapObjects", GoName: "PtrMap", Type: ir.Map(ir.Primitive("string"), ir.Struct("Address")), Optional: true}, withMap.Fields[2])
+	assert.Equal(t, []string{"Address"}, withMap.References())
+}
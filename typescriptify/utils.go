@@ -3,6 +3,7 @@ package typescriptify
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 func indentLines(str string, i int) string {
@@ -13,6 +14,96 @@ func indentLines(str string, i int) string {
 	return strings.Join(lines, "\n")
 }
 
+// tsDocComment converts doc into the lines of a TSDoc comment. One-line docs are rendered as
+// `/** doc */`, multi-line docs (for example with `@deprecated` or `@example` tags) as a block
+// comment with one ` * ` line per doc line. Any `*/` in doc is escaped so that it can't end
+// the comment (and break the generated file).
+func tsDocComment(doc string) []string {
+	doc = strings.TrimSpace(strings.ReplaceAll(doc, "\r\n", "\n"))
+	if doc == "" {
+		return nil
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		return []string{"/** " + lines[0] + " */"}
+	}
+	result := []string{"/**"}
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			result = append(result, " *")
+		} else {
+			result = append(result, " * "+line)
+		}
+	}
+	return append(result, " */")
+}
+
+// docCommentPrefix returns the TSDoc comment to be put before a declaration, or an empty
+// string if there is no doc.
+func docCommentPrefix(doc string) string {
+	result := ""
+	for _, line := range tsDocComment(doc) {
+		result += line + "\n"
+	}
+	return result
+}
+
+// tsBuiltinTypes are the typescript types, keywords and generic wrappers which are never imported.
+var tsBuiltinTypes = map[string]bool{
+	"string": true, "number": true, "boolean": true, "bigint": true, "symbol": true, "object": true,
+	"null": true, "undefined": true, "any": true, "unknown": true, "never": true, "void": true,
+	"true": true, "false": true, "keyof": true, "typeof": true, "readonly": true, "infer": true,
+	"extends": true, "unique": true, "is": true, "asserts": true, "in": true,
+	"Array": true, "ReadonlyArray": true, "Record": true, "Partial": true, "Required": true, "Readonly": true,
+	"Pick": true, "Omit": true, "Exclude": true, "Extract": true, "NonNullable": true, "ReturnType": true,
+	"Parameters": true, "Awaited": true, "Promise": true, "Map": true, "Set": true, "ReadonlyMap": true,
+	"ReadonlySet": true, "WeakMap": true, "WeakSet": true, "Date": true, "RegExp": true, "Error": true,
+	"Uint8Array": true, "ArrayBuffer": true, "Function": true, "Object": true,
+}
+
+// tsTypeIdentifiers returns the identifiers of the types referenced in a typescript type which may be
+// imported (`Money` and `Currency` in `Record<Currency, Money> | null`). Built-in types, property names,
+// string literals and members of namespaces (only the namespace is returned) are skipped.
+func tsTypeIdentifiers(tsType string) []string {
+	var result []string
+	found := map[string]bool{}
+	for i := 0; i < len(tsType); {
+		r, size := utf8.DecodeRuneInString(tsType[i:])
+		switch {
+		case r == '"' || r == '\'' || r == '`':
+			end := strings.IndexRune(tsType[i+size:], r)
+			if end < 0 {
+				return result
+			}
+			i += size + end + size
+		case isIdentifierRune(r):
+			end := strings.IndexFunc(tsType[i:], func(r rune) bool { return !isIdentifierRune(r) })
+			if end < 0 {
+				end = len(tsType) - i
+			}
+			name := tsType[i : i+end]
+			rest := strings.TrimLeft(tsType[i+end:], " \t\n")
+			property := strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "?:")
+			member := strings.HasSuffix(strings.TrimRight(tsType[:i], " \t\n"), ".")
+			if !property && !member && !tsBuiltinTypes[name] && !unicode.IsDigit(r) && !found[name] {
+				found[name] = true
+				result = append(result, name)
+			}
+			i += end
+		default:
+			i += size
+		}
+	}
+	return result
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
 type CamelCaseOptions struct {
 	PreserveConsecutiveUppercase bool
 }
@@ -1,6 +1,10 @@
 package typescriptify
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func TestIndentLines(t *testing.T) {
 	t.Parallel()
@@ -211,3 +215,67 @@ func TestCamelCasePreserveConsecutiveUpperCase(t *testing.T) {
 		})
 	}
 }
+
+func TestTSDocComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "empty",
+			input:    " \n",
+			expected: nil,
+		},
+		{
+			name:     "single line",
+			input:    "A comment",
+			expected: []string{"/** A comment */"},
+		},
+		{
+			name:     "multi line",
+			input:    "A comment\n\n@deprecated use something else",
+			expected: []string{"/**", " * A comment", " *", " * @deprecated use something else", " */"},
+		},
+		{
+			name:     "escaped end of comment",
+			input:    "a */ b",
+			expected: []string{"/** a *\\/ b */"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := tsDocComment(test.input)
+			if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTSTypeIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	for tsType, expected := range map[string][]string{
+		"Money":                        {"Money"},
+		"Money | null":                 {"Money"},
+		"{[key: string]: X}":           {"X"},
+		"$Big_1[]":                     {"$Big_1"},
+		"Record<string, Money>":        {"Money"},
+		"Record<Currency, Money[]>":    {"Currency", "Money"},
+		"Partial<Array<Money>> | Date": {"Money"},
+		"Promise<Money | undefined>":   {"Money"},
+		"{ amount?: Decimal; id: 1 }":  {"Decimal"},
+		"money.Amount | 'Money'":       {"money"},
+		`"Money" | "Cash"`:             nil,
+		"Map<Money, Set<Money>>":       {"Money"},
+		"":                             nil,
+	} {
+		if actual := tsTypeIdentifiers(tsType); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %q for %q, got %q", expected, tsType, actual)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package typescriptify
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+const validateTag = "validate"
+
+// validatorFormats maps go-playground/validator tags to (JSON schema) formats.
+var validatorFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"http_url": "uri",
+	"uuid":     "uuid",
+	"uuid3":    "uuid",
+	"uuid4":    "uuid",
+	"uuid5":    "uuid",
+	"ip":       "ip",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"fqdn":     "hostname",
+	"datetime": "date-time",
+}
+
+// validatorPatterns maps go-playground/validator tags to regular expressions.
+var validatorPatterns = map[string]string{
+	"alpha":       `^[a-zA-Z]+$`,
+	"alphanum":    `^[a-zA-Z0-9]+$`,
+	"numeric":     `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+	"number":      `^[0-9]+$`,
+	"hexadecimal": `^(0[xX])?[0-9a-fA-F]+$`,
+	"lowercase":   `^[^A-Z]*$`,
+	"uppercase":   `^[^a-z]*$`,
+}
+
+// constraints are the validation rules of a field, parsed from its `validate` tag
+// (see https://github.com/go-playground/validator).
+//
+// Depending on the field kind `min`, `max`, `len`, `gt`, `gte`, `lt` and `lte` limit the length
+// (strings), the number of items (slices, arrays and maps) or the value (numbers).
+type constraints struct {
+	required bool
+	ir.Constraints
+}
+
+// parseValidateTag parses a validate tag of a field of the given kind (for pointers the kind of the
+// element). Unknown tags, tags with alternatives (`a|b`) and everything after `dive` (those rules
+// apply to the elements, not the field) are ignored.
+func parseValidateTag(tag string, kind reflect.Kind) (constraints, error) {
+	var c constraints
+	if tag == "" || tag == "-" {
+		return c, nil
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "dive" || rule == "keys" {
+			break
+		}
+		if rule == "" || strings.Contains(rule, "|") {
+			continue
+		}
+		name, param, _ := strings.Cut(rule, "=")
+
+		var err error
+		switch name {
+		case "required":
+			c.required = true
+		case "len", "eq":
+			if name == "eq" && !isNumberKind(kind) && !isCollectionKind(kind) {
+				c.OneOf, c.OneOfStrings = []string{param}, kind == reflect.String
+				break
+			}
+			err = c.setBound(kind, param, ">=", 0)
+			if err == nil {
+				err = c.setBound(kind, param, "<=", 0)
+			}
+		case "min", "gte":
+			err = c.setBound(kind, param, ">=", 0)
+		case "gt":
+			err = c.setBound(kind, param, ">", 1)
+		case "max", "lte":
+			err = c.setBound(kind, param, "<=", 0)
+		case "lt":
+			err = c.setBound(kind, param, "<", -1)
+		case "oneof":
+			c.OneOf, c.OneOfStrings = splitOneOfParam(param), kind == reflect.String
+			if !c.OneOfStrings {
+				for _, val := range c.OneOf {
+					if _, err = strconv.ParseFloat(val, 64); err != nil {
+						break
+					}
+				}
+			}
+		default:
+			if format, found := validatorFormats[name]; found {
+				c.Format = format
+			} else if pattern, found := validatorPatterns[name]; found {
+				c.Pattern = pattern
+			}
+		}
+		if err != nil {
+			return c, fmt.Errorf("invalid validate rule %q: %w", rule, err)
+		}
+	}
+
+	return c, nil
+}
+
+// setBound sets a lower (`>=`, `>`) or upper (`<=`, `<`) bound. For lengths and numbers of items,
+// exclusive bounds are converted to inclusive ones by adding lengthDelta.
+func (c *constraints) setBound(kind reflect.Kind, param, op string, lengthDelta int) error {
+	switch {
+	case isNumberKind(kind):
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return err
+		}
+		switch op {
+		case ">=":
+			c.Minimum = param
+		case ">":
+			c.ExclusiveMinimum = param
+		case "<=":
+			c.Maximum = param
+		case "<":
+			c.ExclusiveMaximum = param
+		}
+	case kind == reflect.String || isCollectionKind(kind):
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return err
+		}
+		n += lengthDelta
+		min, max := &c.MinLength, &c.MaxLength
+		if kind != reflect.String {
+			min, max = &c.MinItems, &c.MaxItems
+		}
+		if op == ">=" || op == ">" {
+			*min = &n
+		} else {
+			*max = &n
+		}
+	}
+	return nil
+}
+
+// constraintsDoc returns the constraints as JSDoc annotations, one per line.
+func constraintsDoc(c *ir.Constraints) string {
+	if c == nil {
+		return ""
+	}
+
+	var lines []string
+	addInt := func(annotation string, val *int) {
+		if val != nil {
+			lines = append(lines, fmt.Sprintf("@%s %d", annotation, *val))
+		}
+	}
+	addString := func(annotation string, val string) {
+		if val != "" {
+			lines = append(lines, fmt.Sprintf("@%s %s", annotation, val))
+		}
+	}
+
+	addInt("minLength", c.MinLength)
+	addInt("maxLength", c.MaxLength)
+	addInt("minItems", c.MinItems)
+	addInt("maxItems", c.MaxItems)
+	addString("minimum", c.Minimum)
+	addString("exclusiveMinimum", c.ExclusiveMinimum)
+	addString("maximum", c.Maximum)
+	addString("exclusiveMaximum", c.ExclusiveMaximum)
+	addString("pattern", c.Pattern)
+	addString("format", c.Format)
+	if len(c.OneOf) > 0 {
+		values := make([]string, len(c.OneOf))
+		for n, val := range c.OneOf {
+			if c.OneOfStrings {
+				values[n] = strconv.Quote(val)
+			} else {
+				values[n] = val
+			}
+		}
+		addString("oneOf", strings.Join(values, " | "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// splitOneOfParam splits the space separated `oneof` values, values with spaces can be quoted
+// with single quotes (`oneof='red green' 'blue'`).
+func splitOneOfParam(param string) []string {
+	var values []string
+	var current strings.Builder
+	quoted, hasValue := false, false
+	for _, r := range param {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			hasValue = true
+		case r == ' ' && !quoted:
+			if hasValue {
+				values = append(values, current.String())
+			}
+			current.Reset()
+			hasValue = false
+		default:
+			current.WriteRune(r)
+			hasValue = true
+		}
+	}
+	if hasValue {
+		values = append(values, current.String())
+	}
+	return values
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isCollectionKind(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
@@ -0,0 +1,88 @@
+package typescriptify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValidateTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tag      string
+		kind     reflect.Kind
+		expected string
+		required bool
+	}{
+		{
+			name:     "empty",
+			tag:      "",
+			kind:     reflect.String,
+			expected: "",
+		},
+		{
+			name:     "string length and format",
+			tag:      "required,min=3,max=64,email",
+			kind:     reflect.String,
+			expected: "@minLength 3\n@maxLength 64\n@format email",
+			required: true,
+		},
+		{
+			name:     "exclusive string length",
+			tag:      "gt=3,lt=10",
+			kind:     reflect.String,
+			expected: "@minLength 4\n@maxLength 9",
+		},
+		{
+			name:     "number range",
+			tag:      "gte=0.5,lt=100",
+			kind:     reflect.Float64,
+			expected: "@minimum 0.5\n@exclusiveMaximum 100",
+		},
+		{
+			name:     "slice items, rules after dive are ignored",
+			tag:      "omitempty,len=2,dive,min=3",
+			kind:     reflect.Slice,
+			expected: "@minItems 2\n@maxItems 2",
+		},
+		{
+			name:     "string oneof",
+			tag:      "oneof=red 'light green' blue",
+			kind:     reflect.String,
+			expected: `@oneOf "red" | "light green" | "blue"`,
+		},
+		{
+			name:     "number oneof",
+			tag:      "oneof=1 2 3",
+			kind:     reflect.Int,
+			expected: `@oneOf 1 | 2 | 3`,
+		},
+		{
+			name:     "pattern, alternatives are ignored",
+			tag:      "alphanum,email|url",
+			kind:     reflect.String,
+			expected: "@pattern ^[a-zA-Z0-9]+$",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := parseValidateTag(test.tag, test.kind)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, constraintsDoc(&c.Constraints))
+			assert.Equal(t, test.required, c.required)
+		})
+	}
+}
+
+func TestParseInvalidValidateTag(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseValidateTag("min=three", reflect.String)
+	assert.NotNil(t, err)
+	_, err = parseValidateTag("oneof=1 two", reflect.Int)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,58 @@
+package typescriptify
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+)
+
+// writeFile writes a generated file if its content changes, with a backup of the existing one (if
+// BackupDir is set). Returns true if the file was written.
+func (t TypeScriptify) writeFile(fileName, code string) (bool, error) {
+	content := []byte(fileHeader + code)
+	existing, err := os.ReadFile(fileName)
+	if err == nil && bytes.Equal(existing, content) {
+		return false, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	if len(t.BackupDir) > 0 {
+		if err := t.backup(fileName); err != nil {
+			return false, err
+		}
+	}
+	if err := writeFileAtomic(fileName, content); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeFileAtomic writes a temporary file in the same directory and renames it, keeping the permissions of
+// an existing file.
+func writeFileAtomic(fileName string, content []byte) error {
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(fileName); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(fileName), "."+filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpFileName := f.Name()
+	defer os.Remove(tmpFileName) // Fails after the rename
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpFileName, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpFileName, fileName)
+}
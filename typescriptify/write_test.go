@@ -0,0 +1,73 @@
+package typescriptify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "models.ts")
+
+	converter := New().Add(Holliday{}).AddEnum(allWeekdaysV1).WithBackupDir(dir)
+
+	changed, err := converter.UpdateFile(fileName)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	assert.Nil(t, converter.Check(fileName))
+
+	// Unchanged files are not written (or backed up):
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour).Truncate(time.Second)
+	assert.Nil(t, os.Chtimes(fileName, lastWeek, lastWeek))
+	changed, err = converter.UpdateFile(fileName)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+	info, err := os.Stat(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, lastWeek, info.ModTime())
+
+	// Permissions are preserved:
+	assert.Nil(t, os.Chmod(fileName, 0600))
+	changed, err = converter.WithPrefix("API_").UpdateFile(fileName)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	info, err = os.Stat(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.Nil(t, err)
+	assert.Len(t, files, 2) // The file and one backup, no temporary files
+}
+
+func TestUpdateFileConversionError(t *testing.T) {
+	t.Parallel()
+	fileName := filepath.Join(t.TempDir(), "models.ts")
+	assert.Nil(t, os.WriteFile(fileName, []byte("existing"), 0644))
+
+	_, err := New().Add(Holliday{}).WithOrder("random").WithBackupDir("").UpdateFile(fileName)
+	assert.NotNil(t, err)
+
+	byts, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "existing", string(byts))
+}
+
+func TestUpdateDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	converter := New().Add(Team{}).WithInterface(true).WithBackupDir("")
+	changed, err := converter.UpdateDir(dir)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	changed, err = converter.UpdateDir(dir)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
@@ -0,0 +1,178 @@
+package typescriptify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GoodNotes/typescriptify-golang-structs/typescriptify/ir"
+)
+
+// zodSchemaSuffix is appended to declaration names for their schemas.
+const zodSchemaSuffix = "Schema"
+
+// zodFormats maps formats (see validatorFormats) to zod string methods.
+var zodFormats = map[string]string{
+	"email":     ".email()",
+	"uri":       ".url()",
+	"uuid":      ".uuid()",
+	"ip":        ".ip()",
+	"ipv4":      `.ip({ version: "v4" })`,
+	"ipv6":      `.ip({ version: "v6" })`,
+	"date-time": ".datetime()",
+}
+
+// zodEmitter creates interfaces and enums with a zod schema for each of them, the constraints of validate tags
+// (see ValidateTags) are checked by the schemas. Struct schemas are typed with their interfaces (so that
+// recursive structs can be validated), and reference other schemas lazily.
+type zodEmitter struct {
+	t          *TypeScriptify
+	customCode map[string]string
+}
+
+func (e *zodEmitter) Header(model *ir.Model, imports []ir.Import) (string, error) {
+	result := "import { z } from \"zod\";\n" + e.t.customImportsCode()
+	for _, imp := range imports {
+		names := imp.Names
+		if imp.Generated {
+			names = nil
+			for _, name := range imp.Names {
+				names = append(names, name, name+zodSchemaSuffix)
+			}
+		}
+		result += fmt.Sprintf("import { %s } from %q;\n", strings.Join(names, ", "), imp.Module)
+	}
+	return result + customCodeHeader(e.customCode), nil
+}
+
+func (e *zodEmitter) Declaration(model *ir.Model, decl *ir.Declaration) (string, error) {
+	t := e.t
+	export := "export "
+	if t.DontExport {
+		export = ""
+	}
+	ts := &typeScriptEmitter{t: t}
+	schemaName := decl.Name + zodSchemaSuffix
+
+	if decl.Kind == ir.KindEnum {
+		return fmt.Sprintf("%s\n%sconst %s = z.nativeEnum(%s);", ts.enum(decl), export, schemaName, decl.Name) +
+			customCodeAfter(e.customCode, decl.Name, false), nil
+	}
+
+	var fields, schemaFields []string
+	for _, fld := range decl.Fields {
+		fields = append(fields, ts.field(fld)...)
+		schemaFields = append(schemaFields, fmt.Sprintf("%s%s: %s,", t.Indent, fld.Name, zodFieldSchema(fld)))
+	}
+	result := docCommentPrefix(decl.Doc) + fmt.Sprintf("%sinterface %s {\n", export, decl.Name)
+	if len(fields) > 0 {
+		result += strings.Join(fields, "\n") + "\n"
+	}
+	result += t.customCodeBlock(e.customCode, decl.Name) + "}\n"
+	result += fmt.Sprintf("%sconst %s: z.ZodType<%s> = z.object({\n", export, schemaName, decl.Name)
+	if len(schemaFields) > 0 {
+		result += strings.Join(schemaFields, "\n") + "\n"
+	}
+	return result + "});" + customCodeAfter(e.customCode, decl.Name, true), nil
+}
+
+func (e *zodEmitter) Footer(model *ir.Model) (string, error) {
+	return e.t.customCodeFooter(model, e.customCode), nil
+}
+
+// zodFieldSchema returns the schema of a field, with its constraints.
+func zodFieldSchema(fld *ir.Field) string {
+	schema := zodSchema(fld.Type, fld.Constraints)
+	if fld.Optional {
+		schema += ".optional()"
+	}
+	return schema
+}
+
+// zodSchema returns the schema of a type, the constraints (if not nil) apply to the type itself (not to the
+// elements of arrays and maps).
+func zodSchema(typ *ir.TypeExpr, c *ir.Constraints) string {
+	if c == nil {
+		c = &ir.Constraints{}
+	}
+	switch typ.Kind {
+	case ir.TypePrimitive:
+		if len(c.OneOf) > 0 {
+			return zodOneOf(c)
+		}
+		switch typ.Name {
+		case "string":
+			return "z.string()" + zodStringChecks(c)
+		case "number":
+			return "z.number()" + zodNumberChecks(c)
+		case "boolean":
+			return "z.boolean()"
+		}
+		return "z.any()"
+	case ir.TypeStruct, ir.TypeEnum:
+		return fmt.Sprintf("z.lazy(() => %s%s)", typ.Name, zodSchemaSuffix)
+	case ir.TypeArray:
+		return fmt.Sprintf("z.array(%s)", zodSchema(typ.Elem, nil)) + zodSizeChecks(".min(%d)", ".max(%d)", c)
+	case ir.TypeMap:
+		// JSON object keys are strings, whatever the type of the map keys:
+		return fmt.Sprintf("z.record(z.string(), %s)", zodSchema(typ.Elem, nil)) +
+			zodSizeChecks(".refine((value) => Object.keys(value).length >= %d)", ".refine((value) => Object.keys(value).length <= %d)", c)
+	}
+	return fmt.Sprintf("z.custom<%s>()", typ.Name)
+}
+
+// zodOneOf returns the schema of the values allowed by `oneof`.
+func zodOneOf(c *ir.Constraints) string {
+	var values []string
+	for _, val := range c.OneOf {
+		if c.OneOfStrings {
+			val = strconv.Quote(val)
+		}
+		values = append(values, val)
+	}
+	if c.OneOfStrings {
+		return fmt.Sprintf("z.enum([%s])", strings.Join(values, ", "))
+	}
+	if len(values) == 1 {
+		return fmt.Sprintf("z.literal(%s)", values[0])
+	}
+	for n, val := range values {
+		values[n] = fmt.Sprintf("z.literal(%s)", val)
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(values, ", "))
+}
+
+func zodStringChecks(c *ir.Constraints) string {
+	result := zodSizeChecks(".min(%d)", ".max(%d)", &ir.Constraints{MinItems: c.MinLength, MaxItems: c.MaxLength})
+	if c.Pattern != "" {
+		result += fmt.Sprintf(".regex(/%s/)", strings.ReplaceAll(c.Pattern, "/", `\/`))
+	}
+	return result + zodFormats[c.Format]
+}
+
+func zodNumberChecks(c *ir.Constraints) string {
+	result := ""
+	for _, check := range []struct{ method, value string }{
+		{"gte", c.Minimum},
+		{"gt", c.ExclusiveMinimum},
+		{"lte", c.Maximum},
+		{"lt", c.ExclusiveMaximum},
+	} {
+		if check.value != "" {
+			result += fmt.Sprintf(".%s(%s)", check.method, check.value)
+		}
+	}
+	return result
+}
+
+// zodSizeChecks returns the checks of the number of items of arrays and maps.
+func zodSizeChecks(minFormat, maxFormat string, c *ir.Constraints) string {
+	result := ""
+	if c.MinItems != nil {
+		result += fmt.Sprintf(minFormat, *c.MinItems)
+	}
+	if c.MaxItems != nil {
+		result += fmt.Sprintf(maxFormat, *c.MaxItems)
+	}
+	return result
+}
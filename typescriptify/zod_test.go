@@ -0,0 +1,168 @@
+package typescriptify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ZodAccount struct {
+	Email    string            `json:"email" validate:"required,max=64,email"`
+	Login    string            `json:"login,omitempty" validate:"min=3,alphanum"`
+	Age      int               `json:"age" validate:"gte=18,lt=130"`
+	Color    string            `json:"color" validate:"oneof=red blue"`
+	Level    int               `json:"level" validate:"oneof=1 2"`
+	Tags     []string          `json:"tags" validate:"max=5,dive,min=1"`
+	Labels   map[string]string `json:"labels" validate:"min=1"`
+	Weekday  Weekday           `json:"weekday"`
+	Parent   *ZodAccount       `json:"parent,omitempty"`
+	Homepage string            `json:"homepage" ts_type:"URL"`
+}
+
+func TestZod(t *testing.T) {
+	t.Parallel()
+
+	converter := New().
+		WithZod(true).
+		WithValidateTags(true).
+		WithBackupDir("").
+		AddEnum(allWeekdaysV1).
+		Add(ZodAccount{})
+
+	desiredResult := `import { z } from "zod";
+
+export enum Weekday {
+	SUNDAY = 0,
+	MONDAY = 1,
+	TUESDAY = 2,
+	WEDNESDAY = 3,
+	THURSDAY = 4,
+	FRIDAY = 5,
+	SATURDAY = 6,
+}
+export const WeekdaySchema = z.nativeEnum(Weekday);
+export interface ZodAccount {
+	/**
+	 * @maxLength 64
+	 * @format email
+	 */
+	email: string;
+	/**
+	 * @minLength 3
+	 * @pattern ^[a-zA-Z0-9]+$
+	 */
+	login?: string;
+	/**
+	 * @minimum 18
+	 * @exclusiveMaximum 130
+	 */
+	age: number;
+	/** @oneOf "red" | "blue" */
+	color: string;
+	/** @oneOf 1 | 2 */
+	level: number;
+	/** @maxItems 5 */
+	tags: string[];
+	/** @minItems 1 */
+	labels: {[key: string]: string};
+	weekday: Weekday;
+	parent?: ZodAccount;
+	homepage: URL;
+}
+export const ZodAccountSchema: z.ZodType<ZodAccount> = z.object({
+	email: z.string().max(64).email(),
+	login: z.string().min(3).regex(/^[a-zA-Z0-9]+$/).optional(),
+	age: z.number().gte(18).lt(130),
+	color: z.enum(["red", "blue"]),
+	level: z.union([z.literal(1), z.literal(2)]),
+	tags: z.array(z.string()).max(5),
+	labels: z.record(z.string(), z.string()).refine((value) => Object.keys(value).length >= 1),
+	weekday: z.lazy(() => WeekdaySchema),
+	parent: z.lazy(() => ZodAccountSchema).optional(),
+	homepage: z.custom<URL>(),
+});`
+	typeScriptCode, err := converter.WithIndent("\t").Convert(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, desiredResult, typeScriptCode)
+}
+
+func TestZodInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := New().WithZod(true).WithJavaScript(true).Add(ZodAccount{}).Convert(nil)
+	assert.NotNil(t, err)
+}
+
+func TestZodCustomCode(t *testing.T) {
+	t.Parallel()
+	fileName := filepath.Join(t.TempDir(), "models.ts")
+	existing := `//[@top:]
+const HOLIDAY_NAMES = ["Christmas"];
+//[end]
+//[Weekday:]
+export const WEEKEND = [Weekday.SATURDAY, Weekday.SUNDAY];
+//[end]
+export interface Holliday {
+	//[Holliday:]
+	extra?: string;
+	//[end]
+}
+//[@Holliday:]
+export const isKnown = (h: Holliday) => HOLIDAY_NAMES.includes(h.name);
+//[end]
+//[@bottom:]
+export default HollidaySchema;
+//[end]`
+	assert.Nil(t, os.WriteFile(fileName, []byte(existing), 0644))
+
+	converter := New().
+		WithZod(true).
+		WithIndent("\t").
+		WithBackupDir("").
+		AddEnum(allWeekdaysV1).
+		Add(Holliday{})
+	assert.Nil(t, converter.ConvertToFile(fileName))
+	assertFileContent(t, fileName, `import { z } from "zod";
+//[@top:]
+const HOLIDAY_NAMES = ["Christmas"];
+//[end]
+
+export enum Weekday {
+	SUNDAY = 0,
+	MONDAY = 1,
+	TUESDAY = 2,
+	WEDNESDAY = 3,
+	THURSDAY = 4,
+	FRIDAY = 5,
+	SATURDAY = 6,
+}
+export const WeekdaySchema = z.nativeEnum(Weekday);
+//[Weekday:]
+export const WEEKEND = [Weekday.SATURDAY, Weekday.SUNDAY];
+//[end]
+export interface Holliday {
+	name: string;
+	weekday: Weekday;
+	//[Holliday:]
+	extra?: string;
+
+	//[end]
+}
+export const HollidaySchema: z.ZodType<Holliday> = z.object({
+	name: z.string(),
+	weekday: z.lazy(() => WeekdaySchema),
+});
+//[@Holliday:]
+export const isKnown = (h: Holliday) => HOLIDAY_NAMES.includes(h.name);
+//[end]
+//[@bottom:]
+export default HollidaySchema;
+//[end]`)
+
+	// Regions are stable:
+	changed, err := converter.UpdateFile(fileName)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}